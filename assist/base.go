@@ -3,7 +3,9 @@ package assist
 import (
 	"bytes"
 	"crypto/md5"
+	"encoding/csv"
 	"fmt"
+	"github.com/csby/gwin/model"
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"io"
 	"os/exec"
@@ -72,6 +74,50 @@ func (s *base) getFields(line string, sep string) []string {
 	return fields
 }
 
+func (s *base) getCsvRows(text []byte) []map[string]string {
+	rows := make([]map[string]string, 0)
+	if len(text) < 1 {
+		return rows
+	}
+	/*
+		"ScopeId","IPAddress","ClientId"
+		"172.16.11.0","172.16.11.19","90-94-97-8b-f5-f8"
+	*/
+
+	reader := csv.NewReader(bytes.NewReader(text))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return rows
+	}
+	c := len(records)
+	if c < 2 {
+		return rows
+	}
+
+	header := records[0]
+	for i := 1; i < c; i++ {
+		record := records[i]
+		row := make(map[string]string)
+		for j := 0; j < len(header) && j < len(record); j++ {
+			row[strings.TrimSpace(header[j])] = strings.TrimSpace(record[j])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (s *base) quoteValues(values ...string) string {
+	items := make([]string, 0)
+	c := len(values)
+	for i := 0; i < c; i++ {
+		items = append(items, fmt.Sprintf("'%s'", strings.ReplaceAll(values[i], "'", "''")))
+	}
+
+	return strings.Join(items, ",")
+}
+
 func (s *base) uniqueId(id string, a ...interface{}) string {
 	o := fmt.Sprint(a...)
 	v := fmt.Sprintf("%s-%s", id, o)
@@ -84,3 +130,54 @@ func (s *base) uniqueId(id string, a ...interface{}) string {
 
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
+
+func (s *base) normalizePath(path string) string {
+	return "/" + strings.Trim(strings.TrimSpace(strings.ReplaceAll(path, "\\", "/")), "/")
+}
+
+func (s *base) parentPath(path string) string {
+	index := strings.LastIndex(path, "/")
+	if index < 1 {
+		return "/"
+	}
+
+	return path[:index]
+}
+
+// resolvePermissions returns the rules applied to path, the nearest rule of each account wins
+// and rules of the repository go before rules of all repositories (*) on the same path.
+func (s *base) resolvePermissions(rules []*model.SvnAccessRule, repository, path string) []*model.SvnPermission {
+	items := make([]*model.SvnPermission, 0)
+	path = s.normalizePath(path)
+	accounts := make(map[string]bool)
+	for current := path; ; current = s.parentPath(current) {
+		for _, name := range []string{repository, "*"} {
+			for i := 0; i < len(rules); i++ {
+				rule := rules[i]
+				if !strings.EqualFold(rule.Repository, name) || s.normalizePath(rule.Path) != current {
+					continue
+				}
+				account := strings.ToLower(rule.AccountId)
+				if accounts[account] {
+					continue
+				}
+				accounts[account] = true
+				items = append(items, &model.SvnPermission{
+					AccountId:   rule.AccountId,
+					AccountName: rule.AccountName,
+					AccessLevel: rule.AccessLevel,
+					Inherited:   current != path,
+					Group:       rule.Group,
+				})
+			}
+			if name == "*" {
+				break
+			}
+		}
+		if current == "/" {
+			break
+		}
+	}
+
+	return items
+}
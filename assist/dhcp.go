@@ -9,10 +9,23 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	dhcpLeaseFields = "ScopeId,IPAddress,ClientId,HostName," +
+		"@{n='LeaseExpiryTime';e={if($_.LeaseExpiryTime){$_.LeaseExpiryTime.ToString('s')}}}," +
+		"AddressState,ClientType,Description"
+)
+
+const (
+	dhcpDefaultConcurrency = 4
 )
 
 type Dhcp struct {
 	base
+
+	Concurrency int
 }
 
 func (s *Dhcp) GetFilters() ([]*model.DhcpFilter, error) {
@@ -49,18 +62,50 @@ func (s *Dhcp) DeleteFilter(address string) error {
 	return err
 }
 
+func (s *Dhcp) GetFilterList() (*model.DhcpFilterList, error) {
+	output, err := s.runShell("Get-DhcpServerv4FilterList",
+		"|", "Select", "Allow,Deny", "|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := s.getCsvRows(output)
+	if len(rows) < 1 {
+		return nil, fmt.Errorf("filter list not found")
+	}
+
+	return &model.DhcpFilterList{
+		Allow: strings.ToLower(rows[0]["Allow"]) == "true",
+		Deny:  strings.ToLower(rows[0]["Deny"]) == "true",
+	}, nil
+}
+
+func (s *Dhcp) SetFilterList(v *model.DhcpFilterList) error {
+	if v == nil {
+		return fmt.Errorf("filter list is nil")
+	}
+
+	_, err := s.runShell("Set-DhcpServerv4FilterList",
+		"-Allow", fmt.Sprintf("$%t", v.Allow), "-Deny", fmt.Sprintf("$%t", v.Deny))
+	return err
+}
+
 func (s *Dhcp) GetLeases() ([]*model.DhcpLease, error) {
-	output, err := s.runShell("Get-DhcpServerV4Scope", "|", "Select", "ScopeId")
+	list, err := s.GetScopeLeases()
 	if err != nil {
 		return nil, err
 	}
-	results := make([]*model.DhcpLease, 0)
-	scopes := s.getScopeIds(output)
-	sc := len(scopes)
-	if sc < 1 {
-		return results, nil
+
+	return s.flattenLeases(list)
+}
+
+func (s *Dhcp) GetScopeLeases() (*model.DhcpLeaseList, error) {
+	scopes, err := s.getScopeIds()
+	if err != nil {
+		return nil, err
 	}
 
+	list := &model.DhcpLeaseList{}
 	comments := make(map[string]string)
 	waitGroup := &sync.WaitGroup{}
 	waitGroup.Add(1)
@@ -68,6 +113,7 @@ func (s *Dhcp) GetLeases() ([]*model.DhcpLease, error) {
 		defer waitGroup.Done()
 		fs, fe := s.GetFilters()
 		if fe != nil {
+			list.FilterError = fe.Error()
 			return
 		}
 		fc := len(fs)
@@ -80,44 +126,67 @@ func (s *Dhcp) GetLeases() ([]*model.DhcpLease, error) {
 		}
 	}()
 
-	leases := make([][]*model.DhcpLease, 0)
-	for si := 0; si < sc; si++ {
-		scope := scopes[si]
-		if len(scope) < 1 {
-			continue
+	list.Scopes = s.getScopeLeases(model.DhcpFamilyIpV4, scopes, func(scopeId string) ([]*model.DhcpLease, error) {
+		o, e := s.runShell("Get-DhcpServerV4Lease", "-ScopeId", scopeId,
+			"|", "Select", dhcpLeaseFields,
+			"|", "ConvertTo-Csv", "-NoTypeInformation")
+		if e != nil {
+			return nil, e
 		}
-
-		ls := make([]*model.DhcpLease, 0)
-		li := len(leases)
-		leases = append(leases, ls)
-		waitGroup.Add(1)
-		go func(scopeId string, index int) {
-			defer waitGroup.Done()
-			o, e := s.runShell("Get-DhcpServerV4Lease", "-ScopeId", scope, "|", "Select", "ClientId,IPAddress")
-			if e != nil {
-				return
-			}
-			leases[index] = s.getLeases(o)
-		}(scope, li)
-	}
-
+		return s.getLeases(o), nil
+	})
 	waitGroup.Wait()
-	for gi := 0; gi < len(leases); gi++ {
-		gs := leases[gi]
+
+	for gi := 0; gi < len(list.Scopes); gi++ {
+		gs := list.Scopes[gi].Leases
 		for ri := 0; ri < len(gs); ri++ {
 			r := gs[ri]
-			if r == nil {
-				continue
-			}
 			comment, ok := comments[r.Address]
 			if ok {
 				r.Comment = comment
 			}
-			results = append(results, r)
 		}
 	}
 
-	return results, err
+	return list, nil
+}
+
+func (s *Dhcp) DeleteLeases(ipV4 ...string) error {
+	addresses := make([]string, 0)
+	c := len(ipV4)
+	for i := 0; i < c; i++ {
+		ip := strings.TrimSpace(ipV4[i])
+		if len(ip) < 1 {
+			continue
+		}
+		addresses = append(addresses, ip)
+	}
+	if len(addresses) < 1 {
+		return fmt.Errorf("ip address is empty")
+	}
+
+	_, err := s.runShell("Remove-DhcpServerv4Lease", "-IPAddress", strings.Join(addresses, ","), "-Confirm:$false")
+	return err
+}
+
+func (s *Dhcp) LoadOui(filePath string) (*model.DhcpOui, error) {
+	if len(filePath) < 1 {
+		return nil, fmt.Errorf("file path is empty")
+	}
+
+	_, err := defaultOui.Load(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetOui(), nil
+}
+
+func (s *Dhcp) GetOui() *model.DhcpOui {
+	return &model.DhcpOui{
+		Source: defaultOui.Source(),
+		Count:  defaultOui.Count(),
+	}
 }
 
 func (s *Dhcp) getFilters(text []byte) []*model.DhcpFilter {
@@ -189,44 +258,107 @@ func (s *Dhcp) getFilter(allow bool, line string) *model.DhcpFilter {
 		comment = fields[2]
 	}
 
+	address = strings.ToUpper(address)
 	return &model.DhcpFilter{
-		Allow:   allow,
-		Address: strings.ToUpper(address),
-		Comment: comment,
+		Allow:      allow,
+		Address:    address,
+		Comment:    comment,
+		Vendor:     defaultOui.Vendor(address),
+		Randomized: defaultOui.Randomized(address),
 	}
 }
 
-func (s Dhcp) getScopeIds(text []byte) []string {
-	results := make([]string, 0)
-	if len(text) < 1 {
-		return results
+func (s *Dhcp) getScopeLeases(family string, scopes []string, fetch func(scopeId string) ([]*model.DhcpLease, error)) []*model.DhcpScopeLeases {
+	concurrency := s.Concurrency
+	if concurrency < 1 {
+		concurrency = dhcpDefaultConcurrency
 	}
-	/*
-		ScopeId
-		-------
-		172.16.11.0
-		172.16.12.0
-	*/
 
-	reader := &bytes.Buffer{}
-	reader.Write(text)
-	for {
-		line, err := reader.ReadString('\n')
-		if err == io.EOF {
-			break
-		}
-		if len(line) < 8 {
+	results := make([]*model.DhcpScopeLeases, 0)
+	for i := 0; i < len(scopes); i++ {
+		if len(scopes[i]) < 1 {
 			continue
 		}
-		if strings.Index(line, "ScopeId") == 0 ||
-			strings.Index(line, "---") == 0 {
+		results = append(results, &model.DhcpScopeLeases{
+			Family:  family,
+			ScopeId: scopes[i],
+			Leases:  make([]*model.DhcpLease, 0),
+		})
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	waitGroup := &sync.WaitGroup{}
+	for i := 0; i < len(results); i++ {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(result *model.DhcpScopeLeases) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+			leases, err := fetch(result.ScopeId)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.Leases = leases
+		}(results[i])
+	}
+	waitGroup.Wait()
+
+	return results
+}
+
+// FlattenLeases returns the leases of the scopes read successfully, and one
+// error message for each scope that failed.
+func (s *Dhcp) FlattenLeases(list *model.DhcpLeaseList) ([]*model.DhcpLease, []string) {
+	results := make([]*model.DhcpLease, 0)
+	errs := make([]string, 0)
+	for i := 0; i < len(list.Scopes); i++ {
+		scope := list.Scopes[i]
+		if len(scope.Error) > 0 {
+			errs = append(errs, fmt.Sprintf("scope %s: %s", scope.ScopeId, scope.Error))
 			continue
 		}
+		results = append(results, scope.Leases...)
+	}
 
-		results = append(results, strings.TrimSpace(line))
+	return results, errs
+}
+
+func (s *Dhcp) FailedScopes(list *model.DhcpLeaseList) map[string]bool {
+	failed := make(map[string]bool)
+	for i := 0; i < len(list.Scopes); i++ {
+		if len(list.Scopes[i].Error) > 0 {
+			failed[list.Scopes[i].ScopeId] = true
+		}
 	}
 
-	return results
+	return failed
+}
+
+func (s *Dhcp) flattenLeases(list *model.DhcpLeaseList) ([]*model.DhcpLease, error) {
+	leases, errs := s.FlattenLeases(list)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return leases, nil
+}
+
+func (s *Dhcp) getScopeIds() ([]string, error) {
+	scopes, err := s.GetScopes()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]string, 0)
+	c := len(scopes)
+	for i := 0; i < c; i++ {
+		results = append(results, scopes[i].ScopeId)
+	}
+
+	return results, nil
 }
 
 func (s Dhcp) getLeases(text []byte) []*model.DhcpLease {
@@ -235,28 +367,14 @@ func (s Dhcp) getLeases(text []byte) []*model.DhcpLease {
 		return results
 	}
 	/*
-		ClientId          IPAddress
-		--------          ---------
-		90-94-97-8b-f5-f8 172.16.11.19
-		9c-b6-d0-e8-38-47 172.16.11.20
+		"ScopeId","IPAddress","ClientId","HostName","LeaseExpiryTime","AddressState","ClientType","Description"
+		"172.16.11.0","172.16.11.19","90-94-97-8b-f5-f8","pc-01.example.com","2020-10-19T08:30:00","Active","Dhcp",""
 	*/
 
-	reader := &bytes.Buffer{}
-	reader.Write(text)
-	for {
-		line, err := reader.ReadString('\n')
-		if err == io.EOF {
-			break
-		}
-		if len(line) < 19 {
-			continue
-		}
-		if strings.Index(line, "ClientId") == 0 ||
-			strings.Index(line, "---") == 0 {
-			continue
-		}
-
-		lease := s.getLease(line)
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		lease := s.getLease(rows[i])
 		if lease != nil {
 			results = append(results, lease)
 		}
@@ -265,28 +383,38 @@ func (s Dhcp) getLeases(text []byte) []*model.DhcpLease {
 	return results
 }
 
-func (s *Dhcp) getLease(line string) *model.DhcpLease {
-	if len(line) < 19 {
+func (s *Dhcp) getLease(row map[string]string) *model.DhcpLease {
+	if row == nil {
 		return nil
 	}
-	/*
-		90-94-97-8b-f5-f8 172.16.11.19
-	*/
 
-	fields := s.getFields(line, " ")
-	if len(fields) < 2 {
-		return nil
-	}
-
-	address := fields[0]
+	address := row["ClientId"]
 	if len(address) != 17 {
 		return nil
 	}
 
-	return &model.DhcpLease{
-		IpV4:    fields[1],
-		Address: strings.ToUpper(address),
+	address = strings.ToUpper(address)
+	lease := &model.DhcpLease{
+		Family:       model.DhcpFamilyIpV4,
+		ScopeId:      row["ScopeId"],
+		IpV4:         row["IPAddress"],
+		Address:      address,
+		Vendor:       defaultOui.Vendor(address),
+		Randomized:   defaultOui.Randomized(address),
+		HostName:     row["HostName"],
+		AddressState: row["AddressState"],
+		ClientType:   row["ClientType"],
+		Description:  row["Description"],
 	}
+	expiry := row["LeaseExpiryTime"]
+	if len(expiry) > 0 {
+		t, err := time.ParseInLocation("2006-01-02T15:04:05", expiry, time.Local)
+		if err == nil {
+			lease.LeaseExpiryTime = t.Format("2006-01-02 15:04:05")
+		}
+	}
+
+	return lease
 }
 
 func (s *Dhcp) runCmd(arg ...string) ([]byte, error) {
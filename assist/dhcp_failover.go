@@ -0,0 +1,145 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	dhcpFailoverFields = "Name,PartnerServer,Mode,State,ServerRole,LoadBalancePercent,ReservePercent," +
+		"MaxClientLeadTime,AutoStateTransition,StateSwitchInterval," +
+		"@{n='ScopeId';e={$_.ScopeId -join ','}}"
+)
+
+func (s *Dhcp) GetFailovers() ([]*model.DhcpFailover, error) {
+	output, err := s.runShell("Get-DhcpServerv4Failover",
+		"|", "Select", dhcpFailoverFields,
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFailovers(output), nil
+}
+
+func (s *Dhcp) GetScopeFailover(scopeId string) (*model.DhcpFailover, error) {
+	failovers, err := s.GetFailovers()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(failovers); i++ {
+		failover := failovers[i]
+		for j := 0; j < len(failover.ScopeIds); j++ {
+			if failover.ScopeIds[j] == scopeId {
+				return failover, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// ReplicateFailover replicates the configuration of the scopes in the named
+// relationship to the partner server; all relationships when name is empty.
+func (s *Dhcp) ReplicateFailover(name string) error {
+	args := []string{"Invoke-DhcpServerv4FailoverReplication"}
+	if len(name) > 0 {
+		args = append(args, "-Name", s.quoteValues(name))
+	}
+	args = append(args, "-Force")
+
+	_, err := s.runShell(args...)
+	return err
+}
+
+func (s *Dhcp) ReplicateScopes(scopeIds ...string) error {
+	if len(scopeIds) < 1 {
+		return fmt.Errorf("scope id is empty")
+	}
+
+	_, err := s.runShell("Invoke-DhcpServerv4FailoverReplication",
+		"-ScopeId", strings.Join(scopeIds, ","), "-Force")
+	return err
+}
+
+func (s *Dhcp) AddPartnerFilter(server string, v *model.DhcpFilter) error {
+	if len(server) < 1 {
+		return fmt.Errorf("partner server is empty")
+	}
+	if v == nil {
+		return fmt.Errorf("filter is nil")
+	}
+
+	list := "Deny"
+	if v.Allow {
+		list = "Allow"
+	}
+	_, err := s.runShell("Add-DhcpServerv4Filter",
+		"-ComputerName", s.quoteValues(server),
+		"-List", list,
+		"-MacAddress", s.quoteValues(v.Address),
+		"-Description", s.quoteValues(v.Comment),
+		"-Force")
+	return err
+}
+
+func (s *Dhcp) DeletePartnerFilter(server, address string) error {
+	if len(server) < 1 {
+		return fmt.Errorf("partner server is empty")
+	}
+	if len(address) < 1 {
+		return fmt.Errorf("address is empty")
+	}
+
+	_, err := s.runShell("Remove-DhcpServerv4Filter",
+		"-ComputerName", s.quoteValues(server),
+		"-MacAddress", s.quoteValues(address))
+	return err
+}
+
+func (s *Dhcp) getFailovers(text []byte) []*model.DhcpFailover {
+	results := make([]*model.DhcpFailover, 0)
+	if len(text) < 1 {
+		return results
+	}
+	/*
+		"Name","PartnerServer","Mode","State","ServerRole","LoadBalancePercent","ReservePercent","MaxClientLeadTime","AutoStateTransition","StateSwitchInterval","ScopeId"
+		"dhcp1-dhcp2","dhcp2.example.com","LoadBalance","Normal","","50","0","01:00:00","False","","172.16.11.0,172.16.12.0"
+	*/
+
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["Name"]) < 1 {
+			continue
+		}
+
+		item := &model.DhcpFailover{
+			Name:                row["Name"],
+			PartnerServer:       row["PartnerServer"],
+			Mode:                row["Mode"],
+			State:               row["State"],
+			ServerRole:          row["ServerRole"],
+			MaxClientLeadTime:   row["MaxClientLeadTime"],
+			AutoStateTransition: strings.EqualFold(row["AutoStateTransition"], "True"),
+			StateSwitchInterval: row["StateSwitchInterval"],
+			ScopeIds:            make([]string, 0),
+		}
+		item.LoadBalancePercent, _ = strconv.Atoi(row["LoadBalancePercent"])
+		item.ReservePercent, _ = strconv.Atoi(row["ReservePercent"])
+		scopeIds := strings.Split(row["ScopeId"], ",")
+		for j := 0; j < len(scopeIds); j++ {
+			scopeId := strings.TrimSpace(scopeIds[j])
+			if len(scopeId) > 0 {
+				item.ScopeIds = append(item.ScopeIds, scopeId)
+			}
+		}
+
+		results = append(results, item)
+	}
+
+	return results
+}
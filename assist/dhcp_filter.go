@@ -0,0 +1,293 @@
+package assist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"net"
+	"strconv"
+	"strings"
+)
+
+func (s *Dhcp) NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if len(address) < 1 {
+		return "", fmt.Errorf("address is empty")
+	}
+	_, err := net.ParseMAC(address)
+	if err != nil {
+		return "", fmt.Errorf("address '%s' is invalid", address)
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(address, ":", "-")), nil
+}
+
+func (s *Dhcp) ExportFilters(format string) (*model.DhcpFilterFile, error) {
+	filters, err := s.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.formatFilters(format, filters)
+}
+
+func (s *Dhcp) ImportFilters(targets []*model.DhcpFilter, remove, dryRun bool) (*model.DhcpFilterImportResult, error) {
+	filters, err := s.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.diffFilters(filters, targets, remove)
+	result.DryRun = dryRun
+	if result.DryRun {
+		return result, nil
+	}
+
+	removed := make([]*model.DhcpFilter, 0)
+	for i := 0; i < len(result.Removed); i++ {
+		item := result.Removed[i]
+		err = s.DeleteFilter(item.Address)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("delete %s: %v", item.Address, err))
+			continue
+		}
+		removed = append(removed, item)
+	}
+	updated := make([]*model.DhcpFilter, 0)
+	for i := 0; i < len(result.Updated); i++ {
+		item := result.Updated[i]
+		_, err = s.ModifyFilter(filters, item.Address, item)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("update %s: %v", item.Address, err))
+			continue
+		}
+		updated = append(updated, item)
+	}
+	added := make([]*model.DhcpFilter, 0)
+	for i := 0; i < len(result.Added); i++ {
+		item := result.Added[i]
+		err = s.AddFilter(item)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("add %s: %v", item.Address, err))
+			continue
+		}
+		added = append(added, item)
+	}
+	result.Removed = removed
+	result.Updated = updated
+	result.Added = added
+
+	return result, nil
+}
+
+func (s *Dhcp) ParseFilters(format, content string) ([]*model.DhcpFilter, error) {
+	items := make([]*model.DhcpFilter, 0)
+	if format == model.DhcpFilterFormatJson {
+		err := json.Unmarshal([]byte(content), &items)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(format) < 1 || format == model.DhcpFilterFormatCsv {
+		reader := csv.NewReader(strings.NewReader(content))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(records); i++ {
+			record := records[i]
+			if len(record) < 2 {
+				continue
+			}
+			if i == 0 && strings.ToLower(strings.TrimSpace(record[1])) == "address" {
+				continue
+			}
+
+			allow, err := s.parseFilterAllow(record[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			item := &model.DhcpFilter{
+				Allow:   allow,
+				Address: record[1],
+			}
+			if len(record) > 2 {
+				item.Comment = strings.TrimSpace(record[2])
+			}
+			items = append(items, item)
+		}
+	} else {
+		return nil, fmt.Errorf("format '%s' is not supported", format)
+	}
+
+	addresses := make(map[string]bool)
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		if item == nil {
+			return nil, fmt.Errorf("item %d: filter is nil", i+1)
+		}
+		address, err := s.NormalizeAddress(item.Address)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %v", i+1, err)
+		}
+		if addresses[address] {
+			return nil, fmt.Errorf("item %d: address '%s' is duplicated", i+1, address)
+		}
+		addresses[address] = true
+		item.Address = address
+	}
+
+	return items, nil
+}
+
+func (s *Dhcp) formatFilters(format string, filters []*model.DhcpFilter) (*model.DhcpFilterFile, error) {
+	file := &model.DhcpFilterFile{
+		Format: format,
+	}
+	if len(file.Format) < 1 {
+		file.Format = model.DhcpFilterFormatCsv
+	}
+
+	if file.Format == model.DhcpFilterFormatJson {
+		data, err := json.MarshalIndent(filters, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+		file.Content = string(data)
+	} else if file.Format == model.DhcpFilterFormatCsv {
+		buf := &bytes.Buffer{}
+		writer := csv.NewWriter(buf)
+		writer.Write([]string{"allow", "address", "comment"})
+		for i := 0; i < len(filters); i++ {
+			filter := filters[i]
+			writer.Write([]string{strconv.FormatBool(filter.Allow), filter.Address, filter.Comment})
+		}
+		writer.Flush()
+		err := writer.Error()
+		if err != nil {
+			return nil, err
+		}
+		file.Content = buf.String()
+	} else {
+		return nil, fmt.Errorf("format '%s' is not supported", format)
+	}
+
+	return file, nil
+}
+
+func (s *Dhcp) diffFilters(filters, targets []*model.DhcpFilter, remove bool) *model.DhcpFilterImportResult {
+	result := &model.DhcpFilterImportResult{
+		Added:   make([]*model.DhcpFilter, 0),
+		Updated: make([]*model.DhcpFilter, 0),
+		Removed: make([]*model.DhcpFilter, 0),
+		Errors:  make([]string, 0),
+	}
+
+	existed := make(map[string]*model.DhcpFilter)
+	for i := 0; i < len(filters); i++ {
+		filter := filters[i]
+		existed[filter.Address] = filter
+	}
+
+	imported := make(map[string]bool)
+	for i := 0; i < len(targets); i++ {
+		target := targets[i]
+		imported[target.Address] = true
+		filter, ok := existed[target.Address]
+		if !ok {
+			result.Added = append(result.Added, target)
+		} else if filter.Allow != target.Allow || filter.Comment != target.Comment {
+			result.Updated = append(result.Updated, target)
+		} else {
+			result.Unchanged++
+		}
+	}
+
+	if remove {
+		for i := 0; i < len(filters); i++ {
+			filter := filters[i]
+			if !imported[filter.Address] {
+				result.Removed = append(result.Removed, filter)
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *Dhcp) parseFilterAllow(v string) (bool, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "allow" {
+		return true, nil
+	} else if v == "deny" {
+		return false, nil
+	}
+
+	allow, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("allow '%s' is invalid", v)
+	}
+
+	return allow, nil
+}
+
+func (s *Dhcp) ModifyFilter(filters []*model.DhcpFilter, address string, filter *model.DhcpFilter) (*model.DhcpFilterModifyResult, error) {
+	if filter == nil {
+		return nil, fmt.Errorf("filter is nil")
+	}
+	if len(address) < 1 {
+		return nil, fmt.Errorf("address is empty")
+	}
+
+	var err error
+	result := &model.DhcpFilterModifyResult{
+		State: model.DhcpFilterStateUnchanged,
+	}
+	for i := 0; i < len(filters); i++ {
+		item := filters[i]
+		if item.Address == address {
+			result.Original = item
+		} else if item.Address == filter.Address {
+			return nil, fmt.Errorf("filter '%s' already exist", filter.Address)
+		}
+	}
+	if result.Original == nil {
+		return nil, fmt.Errorf("filter '%s' not exist", address)
+	}
+
+	if address == filter.Address {
+		err = s.DeleteFilter(address)
+		if err != nil {
+			return result, err
+		}
+		err = s.AddFilter(filter)
+		if err != nil {
+			re := s.AddFilter(result.Original)
+			if re != nil {
+				result.State = model.DhcpFilterStateLost
+				return result, fmt.Errorf("%v; restore fail: %v", err, re)
+			}
+			return result, err
+		}
+	} else {
+		err = s.AddFilter(filter)
+		if err != nil {
+			return result, err
+		}
+		err = s.DeleteFilter(address)
+		if err != nil {
+			re := s.DeleteFilter(filter.Address)
+			if re != nil {
+				result.State = model.DhcpFilterStateDuplicated
+				return result, fmt.Errorf("%v; rollback fail: %v", err, re)
+			}
+			return result, err
+		}
+	}
+
+	result.State = model.DhcpFilterStateModified
+	return result, nil
+}
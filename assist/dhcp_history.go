@@ -0,0 +1,241 @@
+package assist
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dhcpHistoryTimeFormat    = "2006-01-02 15:04:05"
+	dhcpHistoryCorruptSuffix = ".corrupt"
+)
+
+type dhcpLeaseInterval struct {
+	ScopeId   string    `json:"scopeId"`
+	IpV4      string    `json:"ipV4"`
+	Address   string    `json:"address"`
+	HostName  string    `json:"hostName"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Active    bool      `json:"active"`
+}
+
+// DhcpHistory keeps lease assignment intervals per IP/MAC pair in a local json file
+type DhcpHistory struct {
+	sync.RWMutex
+
+	FilePath string
+
+	intervals []*dhcpLeaseInterval
+}
+
+func (s *DhcpHistory) Load() error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.intervals = make([]*dhcpLeaseInterval, 0)
+	data, err := ioutil.ReadFile(s.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	err = json.Unmarshal(data, &s.intervals)
+	if err != nil {
+		s.intervals = make([]*dhcpLeaseInterval, 0)
+		// keeps the corrupt file instead of overwriting it on the next save
+		re := os.Rename(s.FilePath, s.FilePath+dhcpHistoryCorruptSuffix)
+		if re != nil {
+			return fmt.Errorf("%v; move aside fail: %v", err, re)
+		}
+		return fmt.Errorf("%v, moved to %s", err, s.FilePath+dhcpHistoryCorruptSuffix)
+	}
+
+	return nil
+}
+
+func (s *DhcpHistory) Save() error {
+	s.RLock()
+	data, err := json.Marshal(s.intervals)
+	s.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	folder := filepath.Dir(s.FilePath)
+	_, err = os.Stat(folder)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(folder, 0777)
+		if err != nil {
+			return err
+		}
+	}
+
+	tempPath := s.FilePath + ".tmp"
+	err = ioutil.WriteFile(tempPath, data, 0666)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, s.FilePath)
+}
+
+// Record merges a lease snapshot taken at now, an active pair not seen for
+// longer than maxGap starts a new interval; intervals of the failed scopes are kept open.
+func (s *DhcpHistory) Record(leases []*model.DhcpLease, failedScopes map[string]bool, now time.Time, maxGap time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	opened := make(map[string]*dhcpLeaseInterval)
+	for i := 0; i < len(s.intervals); i++ {
+		item := s.intervals[i]
+		if item.Active {
+			opened[s.key(item.IpV4, item.Address)] = item
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < len(leases); i++ {
+		lease := leases[i]
+		if lease == nil || len(lease.IpV4) < 1 {
+			continue
+		}
+		if !strings.HasPrefix(lease.AddressState, "Active") {
+			continue
+		}
+		key := s.key(lease.IpV4, lease.Address)
+		seen[key] = true
+
+		item, ok := opened[key]
+		if ok && now.Sub(item.EndTime) <= maxGap {
+			item.EndTime = now
+			if len(lease.HostName) > 0 {
+				item.HostName = lease.HostName
+			}
+			continue
+		}
+		if ok {
+			item.Active = false
+		}
+
+		s.intervals = append(s.intervals, &dhcpLeaseInterval{
+			ScopeId:   lease.ScopeId,
+			IpV4:      lease.IpV4,
+			Address:   lease.Address,
+			HostName:  lease.HostName,
+			StartTime: now,
+			EndTime:   now,
+			Active:    true,
+		})
+	}
+
+	for key, item := range opened {
+		if !seen[key] && !failedScopes[item.ScopeId] {
+			item.Active = false
+		}
+	}
+}
+
+func (s *DhcpHistory) Prune(before time.Time) int {
+	s.Lock()
+	defer s.Unlock()
+
+	intervals := make([]*dhcpLeaseInterval, 0)
+	for i := 0; i < len(s.intervals); i++ {
+		item := s.intervals[i]
+		if item.EndTime.Before(before) {
+			continue
+		}
+		intervals = append(intervals, item)
+	}
+	count := len(s.intervals) - len(intervals)
+	s.intervals = intervals
+
+	return count
+}
+
+func (s *DhcpHistory) Query(filter *model.DhcpLeaseHistoryFilter) ([]*model.DhcpLeaseRecord, error) {
+	var start, end time.Time
+	var err error
+	if filter == nil {
+		filter = &model.DhcpLeaseHistoryFilter{}
+	}
+	if len(filter.StartTime) > 0 {
+		start, err = time.ParseInLocation(dhcpHistoryTimeFormat, filter.StartTime, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("start time '%s' is invalid", filter.StartTime)
+		}
+	}
+	if len(filter.EndTime) > 0 {
+		end, err = time.ParseInLocation(dhcpHistoryTimeFormat, filter.EndTime, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("end time '%s' is invalid", filter.EndTime)
+		}
+	}
+	address := strings.ToUpper(strings.ReplaceAll(filter.Address, ":", "-"))
+
+	s.RLock()
+	defer s.RUnlock()
+
+	results := make([]*model.DhcpLeaseRecord, 0)
+	for i := 0; i < len(s.intervals); i++ {
+		item := s.intervals[i]
+		if len(filter.IpV4) > 0 && item.IpV4 != filter.IpV4 {
+			continue
+		}
+		if len(address) > 0 && item.Address != address {
+			continue
+		}
+		if !start.IsZero() && item.EndTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && item.StartTime.After(end) {
+			continue
+		}
+
+		results = append(results, &model.DhcpLeaseRecord{
+			ScopeId:   item.ScopeId,
+			IpV4:      item.IpV4,
+			Address:   item.Address,
+			HostName:  item.HostName,
+			StartTime: item.StartTime.Format(dhcpHistoryTimeFormat),
+			EndTime:   item.EndTime.Format(dhcpHistoryTimeFormat),
+			Active:    item.Active,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].StartTime < results[j].StartTime
+	})
+
+	return results, nil
+}
+
+func (s *DhcpHistory) LastSeen() map[string]time.Time {
+	s.RLock()
+	defer s.RUnlock()
+
+	results := make(map[string]time.Time)
+	for i := 0; i < len(s.intervals); i++ {
+		item := s.intervals[i]
+		t, ok := results[item.Address]
+		if !ok || item.EndTime.After(t) {
+			results[item.Address] = item.EndTime
+		}
+	}
+
+	return results
+}
+
+func (s *DhcpHistory) key(ip, address string) string {
+	return fmt.Sprintf("%s|%s", ip, address)
+}
@@ -0,0 +1,112 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDhcpHistory_Record(t *testing.T) {
+	history := &DhcpHistory{
+		FilePath: filepath.Join(t.TempDir(), "history.json"),
+	}
+	err := history.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gap := 10 * time.Minute
+	start := time.Date(2020, 10, 13, 8, 0, 0, 0, time.Local)
+	laptop := &model.DhcpLease{IpV4: "172.16.12.40", Address: "90-94-97-8B-F5-F8", AddressState: "Active"}
+	phone := &model.DhcpLease{IpV4: "172.16.12.40", Address: "9C-B6-D0-E8-38-47", AddressState: "Active"}
+
+	history.Record([]*model.DhcpLease{laptop}, nil, start, gap)
+	history.Record([]*model.DhcpLease{laptop}, nil, start.Add(5*time.Minute), gap)
+	history.Record([]*model.DhcpLease{}, nil, start.Add(10*time.Minute), gap)
+	history.Record([]*model.DhcpLease{phone}, nil, start.Add(15*time.Minute), gap)
+	history.Record([]*model.DhcpLease{phone}, nil, start.Add(time.Hour), gap)
+
+	err = history.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	history = &DhcpHistory{FilePath: history.FilePath}
+	err = history.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := history.Query(&model.DhcpLeaseHistoryFilter{IpV4: "172.16.12.40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatal("count: expect 3, actual", len(items), fmtItem(items))
+	}
+	if items[0].Address != laptop.Address || items[0].EndTime != "2020-10-13 08:05:00" || items[0].Active {
+		t.Error("laptop:", fmtItem(items[0]))
+	}
+	if !items[2].Active || items[1].Active {
+		t.Error("phone:", fmtItem(items[1:]))
+	}
+
+	items, err = history.Query(&model.DhcpLeaseHistoryFilter{
+		IpV4:      "172.16.12.40",
+		StartTime: "2020-10-13 08:06:00",
+		EndTime:   "2020-10-13 08:20:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Address != phone.Address {
+		t.Error("time range:", fmtItem(items))
+	}
+
+	count := history.Prune(start.Add(30 * time.Minute))
+	if count != 2 {
+		t.Error("prune: expect 2, actual", count)
+	}
+}
+
+func TestDhcpHistory_RecordFailedScope(t *testing.T) {
+	history := &DhcpHistory{}
+	gap := 10 * time.Minute
+	start := time.Date(2020, 10, 13, 8, 0, 0, 0, time.Local)
+	laptop := &model.DhcpLease{ScopeId: "172.16.12.0", IpV4: "172.16.12.40", Address: "90-94-97-8B-F5-F8", AddressState: "Active"}
+
+	history.Record([]*model.DhcpLease{laptop}, nil, start, gap)
+	history.Record([]*model.DhcpLease{}, map[string]bool{"172.16.12.0": true}, start.Add(5*time.Minute), gap)
+	history.Record([]*model.DhcpLease{laptop}, nil, start.Add(10*time.Minute), gap)
+
+	items, err := history.Query(&model.DhcpLeaseHistoryFilter{IpV4: "172.16.12.40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !items[0].Active || items[0].EndTime != "2020-10-13 08:10:00" {
+		t.Error("failed scope:", fmtItem(items))
+	}
+}
+
+func TestDhcpHistory_LoadCorrupt(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.json")
+	err := ioutil.WriteFile(filePath, []byte("[{"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history := &DhcpHistory{FilePath: filePath}
+	if history.Load() == nil {
+		t.Fatal("corrupt file expected error")
+	}
+	_, err = os.Stat(filePath)
+	if !os.IsNotExist(err) {
+		t.Error("corrupt file not moved aside:", err)
+	}
+	data, err := ioutil.ReadFile(filePath + dhcpHistoryCorruptSuffix)
+	if err != nil || string(data) != "[{" {
+		t.Error("corrupt file not kept:", err)
+	}
+}
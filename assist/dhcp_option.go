@@ -0,0 +1,221 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	dhcpOptionFields = "OptionId,Name,Type,@{n='Value';e={$_.Value -join ';'}},VendorClass,UserClass"
+)
+
+func (s *Dhcp) GetOptions(scopeId, reservedIp string) ([]*model.DhcpOption, error) {
+	args := []string{"Get-DhcpServerv4OptionValue", "-All"}
+	args = append(args, s.getOptionLevelArgs(scopeId, reservedIp)...)
+	args = append(args, "|", "Select", dhcpOptionFields, "|", "ConvertTo-Csv", "-NoTypeInformation")
+	output, err := s.runShell(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getOptions(s.getOptionLevel(scopeId, reservedIp), output), nil
+}
+
+func (s *Dhcp) SetOption(scopeId, reservedIp string, option *model.DhcpOption) error {
+	if option == nil {
+		return fmt.Errorf("option is nil")
+	}
+	if option.OptionId < 1 {
+		return fmt.Errorf("option id is invalid")
+	}
+	if len(option.Value) < 1 {
+		return fmt.Errorf("option value is empty")
+	}
+
+	args := []string{"Set-DhcpServerv4OptionValue",
+		"-OptionId", strconv.Itoa(option.OptionId),
+		"-Value", s.quoteValues(option.Value...)}
+	if len(option.VendorClass) > 0 {
+		args = append(args, "-VendorClass", s.quoteValues(option.VendorClass))
+	}
+	args = append(args, s.getOptionLevelArgs(scopeId, reservedIp)...)
+
+	_, err := s.runShell(args...)
+	return err
+}
+
+func (s *Dhcp) DeleteOption(scopeId, reservedIp string, optionId int, vendorClass string) error {
+	if optionId < 1 {
+		return fmt.Errorf("option id is invalid")
+	}
+
+	args := []string{"Remove-DhcpServerv4OptionValue", "-OptionId", strconv.Itoa(optionId)}
+	if len(vendorClass) > 0 {
+		args = append(args, "-VendorClass", s.quoteValues(vendorClass))
+	}
+	args = append(args, s.getOptionLevelArgs(scopeId, reservedIp)...)
+	args = append(args, "-Confirm:$false")
+
+	_, err := s.runShell(args...)
+	return err
+}
+
+func (s *Dhcp) GetEffectiveOptions(scopeId, reservedIp string) ([]*model.DhcpOption, error) {
+	if len(reservedIp) > 0 && len(scopeId) < 1 {
+		output, err := s.runShell("Get-DhcpServerv4Reservation", "-IPAddress", reservedIp,
+			"|", "Select", "ScopeId", "|", "ConvertTo-Csv", "-NoTypeInformation")
+		if err != nil {
+			return nil, err
+		}
+		rows := s.getCsvRows(output)
+		if len(rows) < 1 {
+			return nil, fmt.Errorf("reservation '%s' not exist", reservedIp)
+		}
+		scopeId = rows[0]["ScopeId"]
+	}
+
+	levels := make([][]*model.DhcpOption, 0)
+	options, err := s.GetOptions("", "")
+	if err != nil {
+		return nil, err
+	}
+	levels = append(levels, options)
+
+	if len(scopeId) > 0 {
+		options, err = s.GetOptions(scopeId, "")
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, options)
+	}
+
+	if len(reservedIp) > 0 {
+		options, err = s.GetOptions("", reservedIp)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, options)
+	}
+
+	return s.mergeOptions(levels...), nil
+}
+
+func (s *Dhcp) GetClasses() ([]*model.DhcpClass, error) {
+	output, err := s.runShell("Get-DhcpServerv4Class",
+		"|", "Select", "Name,Type,Data,Description", "|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DhcpClass, 0)
+	rows := s.getCsvRows(output)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["Name"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpClass{
+			Name:        row["Name"],
+			Type:        row["Type"],
+			Data:        row["Data"],
+			Description: row["Description"],
+		})
+	}
+
+	return results, nil
+}
+
+func (s *Dhcp) mergeOptions(levels ...[]*model.DhcpOption) []*model.DhcpOption {
+	results := make([]*model.DhcpOption, 0)
+	indexes := make(map[string]int)
+
+	lc := len(levels)
+	for li := 0; li < lc; li++ {
+		options := levels[li]
+		oc := len(options)
+		for oi := 0; oi < oc; oi++ {
+			option := options[oi]
+			if option == nil {
+				continue
+			}
+			key := fmt.Sprintf("%d|%s|%s", option.OptionId,
+				strings.ToLower(option.VendorClass), strings.ToLower(option.UserClass))
+			index, ok := indexes[key]
+			if ok {
+				results[index] = option
+			} else {
+				indexes[key] = len(results)
+				results = append(results, option)
+			}
+		}
+	}
+
+	return results
+}
+
+func (s *Dhcp) getOptions(level string, text []byte) []*model.DhcpOption {
+	results := make([]*model.DhcpOption, 0)
+	if len(text) < 1 {
+		return results
+	}
+	/*
+		"OptionId","Name","Type","Value","VendorClass","UserClass"
+		"6","DNS Servers","IPv4Address","172.16.11.2;172.16.11.3","",""
+		"67","Bootfile Name","String","boot\x64\wdsnbp.com","",""
+	*/
+
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		id, err := strconv.Atoi(row["OptionId"])
+		if err != nil {
+			continue
+		}
+
+		option := &model.DhcpOption{
+			OptionId:    id,
+			Name:        row["Name"],
+			Type:        row["Type"],
+			Value:       make([]string, 0),
+			VendorClass: row["VendorClass"],
+			UserClass:   row["UserClass"],
+			Level:       level,
+		}
+		values := strings.Split(row["Value"], ";")
+		for vi := 0; vi < len(values); vi++ {
+			value := strings.TrimSpace(values[vi])
+			if len(value) < 1 {
+				continue
+			}
+			option.Value = append(option.Value, value)
+		}
+
+		results = append(results, option)
+	}
+
+	return results
+}
+
+func (s *Dhcp) getOptionLevel(scopeId, reservedIp string) string {
+	if len(reservedIp) > 0 {
+		return model.DhcpOptionLevelReservation
+	} else if len(scopeId) > 0 {
+		return model.DhcpOptionLevelScope
+	}
+
+	return model.DhcpOptionLevelServer
+}
+
+func (s *Dhcp) getOptionLevelArgs(scopeId, reservedIp string) []string {
+	if len(reservedIp) > 0 {
+		return []string{"-ReservedIP", reservedIp}
+	} else if len(scopeId) > 0 {
+		return []string{"-ScopeId", scopeId}
+	}
+
+	return []string{}
+}
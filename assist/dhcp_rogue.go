@@ -0,0 +1,70 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"time"
+)
+
+func (s *Dhcp) GetRogueReport(leases []*model.DhcpLease, filters []*model.DhcpFilter, lastSeen map[string]time.Time, idleDays int, now time.Time) *model.DhcpRogueReport {
+	report := &model.DhcpRogueReport{
+		CheckTime: now.Format(dhcpHistoryTimeFormat),
+		IdleDays:  idleDays,
+		History:   lastSeen != nil,
+		Unknown:   make([]*model.DhcpLease, 0),
+		Denied:    make([]*model.DhcpLease, 0),
+		Idle:      make([]*model.DhcpRogueFilter, 0),
+	}
+
+	allowed := make(map[string]bool)
+	denied := make(map[string]bool)
+	for i := 0; i < len(filters); i++ {
+		filter := filters[i]
+		if filter.Allow {
+			allowed[filter.Address] = true
+		} else {
+			denied[filter.Address] = true
+		}
+	}
+
+	leased := make(map[string]bool)
+	for i := 0; i < len(leases); i++ {
+		lease := leases[i]
+		if len(lease.Address) < 1 {
+			continue
+		}
+		leased[lease.Address] = true
+		if denied[lease.Address] {
+			report.Denied = append(report.Denied, lease)
+		} else if !allowed[lease.Address] {
+			report.Unknown = append(report.Unknown, lease)
+		}
+	}
+
+	before := now.AddDate(0, 0, -idleDays)
+	for i := 0; i < len(filters); i++ {
+		filter := filters[i]
+		if !filter.Allow {
+			continue
+		}
+
+		if leased[filter.Address] {
+			continue
+		}
+
+		item := &model.DhcpRogueFilter{
+			DhcpFilter: *filter,
+		}
+		if lastSeen != nil {
+			t, ok := lastSeen[filter.Address]
+			if ok {
+				if t.After(before) {
+					continue
+				}
+				item.LastSeen = t.Format(dhcpHistoryTimeFormat)
+			}
+		}
+		report.Idle = append(report.Idle, item)
+	}
+
+	return report
+}
@@ -0,0 +1,283 @@
+package assist
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"net"
+	"strings"
+)
+
+func (s *Dhcp) GetScopes() ([]*model.DhcpScope, error) {
+	output, err := s.runShell("Get-DhcpServerV4Scope",
+		"|", "Select", "ScopeId,Name,SubnetMask,StartRange,EndRange,State",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getScopes(output), nil
+}
+
+func (s *Dhcp) GetScope(scopeId string) (*model.DhcpScope, error) {
+	scopes, err := s.GetScopes()
+	if err != nil {
+		return nil, err
+	}
+	c := len(scopes)
+	for i := 0; i < c; i++ {
+		if scopes[i].ScopeId == scopeId {
+			return scopes[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("scope '%s' not exist", scopeId)
+}
+
+func (s *Dhcp) GetAddressScope(ipV4 string) (*model.DhcpScope, error) {
+	scopes, err := s.GetScopes()
+	if err != nil {
+		return nil, err
+	}
+	ip, ok := s.ipToUint32(ipV4)
+	if !ok {
+		return nil, fmt.Errorf("ip address '%s' invalid", ipV4)
+	}
+	c := len(scopes)
+	for i := 0; i < c; i++ {
+		id, iok := s.ipToUint32(scopes[i].ScopeId)
+		mask, mok := s.ipToUint32(scopes[i].SubnetMask)
+		if iok && mok && ip&mask == id&mask {
+			return scopes[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no scope contains '%s'", ipV4)
+}
+
+func (s *Dhcp) GetExclusions(scopeId string) ([]*model.DhcpExclusion, error) {
+	if len(scopeId) < 1 {
+		return nil, fmt.Errorf("scope id is empty")
+	}
+	output, err := s.runShell("Get-DhcpServerv4ExclusionRange", "-ScopeId", scopeId,
+		"|", "Select", "ScopeId,StartRange,EndRange",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DhcpExclusion, 0)
+	rows := s.getCsvRows(output)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["StartRange"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpExclusion{
+			ScopeId:    row["ScopeId"],
+			StartRange: row["StartRange"],
+			EndRange:   row["EndRange"],
+		})
+	}
+
+	return results, nil
+}
+
+func (s *Dhcp) CheckExclusion(v *model.DhcpExclusion) error {
+	if v == nil {
+		return fmt.Errorf("exclusion is nil")
+	}
+
+	scope, err := s.GetScope(v.ScopeId)
+	if err != nil {
+		return err
+	}
+	reservations, err := s.GetReservations(v.ScopeId)
+	if err != nil {
+		return err
+	}
+	exclusions, err := s.GetExclusions(v.ScopeId)
+	if err != nil {
+		return err
+	}
+
+	return s.checkExclusion(v, scope, reservations, exclusions)
+}
+
+func (s *Dhcp) AddExclusion(v *model.DhcpExclusion) error {
+	if v == nil {
+		return fmt.Errorf("exclusion is nil")
+	}
+
+	_, err := s.runShell("Add-DhcpServerv4ExclusionRange", "-ScopeId", v.ScopeId,
+		"-StartRange", v.StartRange, "-EndRange", v.EndRange)
+	return err
+}
+
+func (s *Dhcp) DeleteExclusion(v *model.DhcpExclusion) error {
+	if v == nil {
+		return fmt.Errorf("exclusion is nil")
+	}
+
+	_, err := s.runShell("Remove-DhcpServerv4ExclusionRange", "-ScopeId", v.ScopeId,
+		"-StartRange", v.StartRange, "-EndRange", v.EndRange, "-Confirm:$false")
+	return err
+}
+
+func (s *Dhcp) GetReservations(scopeId string) ([]*model.DhcpReservation, error) {
+	if len(scopeId) < 1 {
+		return nil, fmt.Errorf("scope id is empty")
+	}
+	output, err := s.runShell("Get-DhcpServerv4Reservation", "-ScopeId", scopeId,
+		"|", "Select", "ScopeId,IPAddress,ClientId,Name,Description",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DhcpReservation, 0)
+	rows := s.getCsvRows(output)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["IPAddress"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpReservation{
+			Family:      model.DhcpFamilyIpV4,
+			ScopeId:     row["ScopeId"],
+			IpV4:        row["IPAddress"],
+			Address:     strings.ToUpper(row["ClientId"]),
+			Name:        row["Name"],
+			Description: row["Description"],
+		})
+	}
+
+	return results, nil
+}
+
+func (s *Dhcp) AddReservation(v *model.DhcpReservation) error {
+	if v == nil {
+		return fmt.Errorf("reservation is nil")
+	}
+	if len(v.ScopeId) < 1 {
+		return fmt.Errorf("scope id is empty")
+	}
+	if len(v.IpV4) < 1 {
+		return fmt.Errorf("ip address is empty")
+	}
+	if len(v.Address) < 1 {
+		return fmt.Errorf("address is empty")
+	}
+
+	args := []string{"Add-DhcpServerv4Reservation", "-ScopeId", v.ScopeId,
+		"-IPAddress", v.IpV4, "-ClientId", v.Address}
+	if len(v.Name) > 0 {
+		args = append(args, "-Name", s.quoteValues(v.Name))
+	}
+	if len(v.Description) > 0 {
+		args = append(args, "-Description", s.quoteValues(v.Description))
+	}
+
+	_, err := s.runShell(args...)
+	return err
+}
+
+func (s *Dhcp) DeleteReservation(ipV4 string) error {
+	if len(ipV4) < 1 {
+		return fmt.Errorf("ip address is empty")
+	}
+
+	_, err := s.runShell("Remove-DhcpServerv4Reservation", "-IPAddress", ipV4, "-Confirm:$false")
+	return err
+}
+
+func (s *Dhcp) checkExclusion(v *model.DhcpExclusion, scope *model.DhcpScope, reservations []*model.DhcpReservation, exclusions []*model.DhcpExclusion) error {
+	start, ok := s.ipToUint32(v.StartRange)
+	if !ok {
+		return fmt.Errorf("start range '%s' is invalid", v.StartRange)
+	}
+	end, ok := s.ipToUint32(v.EndRange)
+	if !ok {
+		return fmt.Errorf("end range '%s' is invalid", v.EndRange)
+	}
+	if start > end {
+		return fmt.Errorf("start range '%s' is greater than end range '%s'", v.StartRange, v.EndRange)
+	}
+
+	if scope != nil {
+		scopeStart, _ := s.ipToUint32(scope.StartRange)
+		scopeEnd, _ := s.ipToUint32(scope.EndRange)
+		if start < scopeStart || end > scopeEnd {
+			return fmt.Errorf("range %s-%s is out of scope range %s-%s",
+				v.StartRange, v.EndRange, scope.StartRange, scope.EndRange)
+		}
+	}
+
+	c := len(reservations)
+	for i := 0; i < c; i++ {
+		reservation := reservations[i]
+		ip, valid := s.ipToUint32(reservation.IpV4)
+		if !valid {
+			continue
+		}
+		if ip >= start && ip <= end {
+			return fmt.Errorf("range %s-%s contains reservation %s (%s)",
+				v.StartRange, v.EndRange, reservation.IpV4, reservation.Name)
+		}
+	}
+
+	c = len(exclusions)
+	for i := 0; i < c; i++ {
+		exclusion := exclusions[i]
+		exclusionStart, _ := s.ipToUint32(exclusion.StartRange)
+		exclusionEnd, _ := s.ipToUint32(exclusion.EndRange)
+		if start <= exclusionEnd && end >= exclusionStart {
+			return fmt.Errorf("range %s-%s overlaps exclusion %s-%s",
+				v.StartRange, v.EndRange, exclusion.StartRange, exclusion.EndRange)
+		}
+	}
+
+	return nil
+}
+
+func (s *Dhcp) getScopes(text []byte) []*model.DhcpScope {
+	results := make([]*model.DhcpScope, 0)
+	if len(text) < 1 {
+		return results
+	}
+	/*
+		"ScopeId","Name","SubnetMask","StartRange","EndRange","State"
+		"172.16.11.0","office","255.255.255.0","172.16.11.10","172.16.11.250","Active"
+	*/
+
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["ScopeId"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpScope{
+			Family:     model.DhcpFamilyIpV4,
+			ScopeId:    row["ScopeId"],
+			Name:       row["Name"],
+			SubnetMask: row["SubnetMask"],
+			StartRange: row["StartRange"],
+			EndRange:   row["EndRange"],
+			State:      row["State"],
+		})
+	}
+
+	return results
+}
+
+func (s *Dhcp) ipToUint32(v string) (uint32, bool) {
+	ip := net.ParseIP(strings.TrimSpace(v)).To4()
+	if ip == nil {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint32(ip), true
+}
@@ -0,0 +1,56 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"strconv"
+)
+
+func (s *Dhcp) GetScopeStatistics() ([]*model.DhcpScopeStatistics, error) {
+	output, err := s.runShell("Get-DhcpServerv4ScopeStatistics",
+		"|", "Select", "ScopeId,AddressesFree,AddressesInUse,ReservedAddress,PendingOffers,PercentageInUse",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getScopeStatistics(output), nil
+}
+
+func (s *Dhcp) getScopeStatistics(text []byte) []*model.DhcpScopeStatistics {
+	results := make([]*model.DhcpScopeStatistics, 0)
+	if len(text) < 1 {
+		return results
+	}
+	/*
+		"ScopeId","AddressesFree","AddressesInUse","ReservedAddress","PendingOffers","PercentageInUse"
+		"172.16.11.0","41","200","12","0","82.98755"
+	*/
+
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["ScopeId"]) < 1 {
+			continue
+		}
+
+		item := &model.DhcpScopeStatistics{
+			ScopeId: row["ScopeId"],
+		}
+		item.Free, _ = strconv.Atoi(row["AddressesFree"])
+		item.InUse, _ = strconv.Atoi(row["AddressesInUse"])
+		item.Reserved, _ = strconv.Atoi(row["ReservedAddress"])
+		item.Pending, _ = strconv.Atoi(row["PendingOffers"])
+		item.Total = item.Free + item.InUse
+		percentage, err := strconv.ParseFloat(row["PercentageInUse"], 64)
+		if err == nil {
+			item.Percentage = float64(int(percentage*100+0.5)) / 100
+		} else if item.Total > 0 {
+			item.Percentage = float64(int(float64(item.InUse)*10000/float64(item.Total)+0.5)) / 100
+		}
+
+		results = append(results, item)
+	}
+
+	return results
+}
@@ -0,0 +1,321 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"testing"
+	"time"
+)
+
+func TestDhcp_GetLeases(t *testing.T) {
+	dhcp := &Dhcp{}
+	items, err := dhcp.GetLeases()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c := len(items)
+	t.Log("count: ", c)
+	for i := 0; i < c; i++ {
+		item := items[i]
+		t.Logf("%3d %15s %s %s %s", i+1, item.IpV4, item.Address, item.HostName, item.LeaseExpiryTime)
+	}
+}
+
+func TestDhcp_getLeases(t *testing.T) {
+	output := `"ScopeId","IPAddress","ClientId","HostName","LeaseExpiryTime","AddressState","ClientType","Description"
+"172.16.11.0","172.16.11.19","90-94-97-8b-f5-f8","pc-01.example.com","2020-10-19T08:30:00","Active","Dhcp",""
+"172.16.11.0","172.16.11.20","9c-b6-d0-e8-38-47","","","Reservation","Both","printer, floor 2"
+"172.16.11.0","172.16.11.21","invalid","","","Declined","Dhcp",""
+`
+	dhcp := &Dhcp{}
+	items := dhcp.getLeases([]byte(output))
+	if len(items) != 2 {
+		t.Fatal("count: expect 2, actual", len(items))
+	}
+
+	item := items[0]
+	if item.Address != "90-94-97-8B-F5-F8" {
+		t.Error("address:", item.Address)
+	}
+	if item.HostName != "pc-01.example.com" {
+		t.Error("host name:", item.HostName)
+	}
+	if item.LeaseExpiryTime != "2020-10-19 08:30:00" {
+		t.Error("lease expiry time:", item.LeaseExpiryTime)
+	}
+
+	item = items[1]
+	if item.LeaseExpiryTime != "" {
+		t.Error("lease expiry time:", item.LeaseExpiryTime)
+	}
+	if item.AddressState != "Reservation" {
+		t.Error("address state:", item.AddressState)
+	}
+	if item.Description != "printer, floor 2" {
+		t.Error("description:", item.Description)
+	}
+}
+
+func TestDhcp_mergeOptions(t *testing.T) {
+	dhcp := &Dhcp{}
+	server := dhcp.getOptions(model.DhcpOptionLevelServer, []byte(`"OptionId","Name","Type","Value","VendorClass","UserClass"
+"6","DNS Servers","IPv4Address","172.16.11.2;172.16.11.3","",""
+"15","DNS Domain Name","String","example.com","",""
+`))
+	scope := dhcp.getOptions(model.DhcpOptionLevelScope, []byte(`"OptionId","Name","Type","Value","VendorClass","UserClass"
+"3","Router","IPv4Address","172.16.11.1","",""
+"6","DNS Servers","IPv4Address","172.16.11.9","",""
+`))
+	reservation := dhcp.getOptions(model.DhcpOptionLevelReservation, []byte(`"OptionId","Name","Type","Value","VendorClass","UserClass"
+"67","Bootfile Name","String","boot\x64\wdsnbp.com","",""
+`))
+
+	items := dhcp.mergeOptions(server, scope, reservation)
+	if len(items) != 4 {
+		t.Fatal("count: expect 4, actual", len(items))
+	}
+	levels := map[int]string{
+		model.DhcpOptionDnsServers:   model.DhcpOptionLevelScope,
+		model.DhcpOptionDomainName:   model.DhcpOptionLevelServer,
+		model.DhcpOptionRouter:       model.DhcpOptionLevelScope,
+		model.DhcpOptionBootFileName: model.DhcpOptionLevelReservation,
+	}
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		if levels[item.OptionId] != item.Level {
+			t.Errorf("option %d: expect level %s, actual %s", item.OptionId, levels[item.OptionId], item.Level)
+		}
+		if item.OptionId == model.DhcpOptionDnsServers {
+			if len(item.Value) != 1 || item.Value[0] != "172.16.11.9" {
+				t.Error("dns servers:", item.Value)
+			}
+		}
+	}
+}
+
+func TestDhcp_checkExclusion(t *testing.T) {
+	dhcp := &Dhcp{}
+	scope := &model.DhcpScope{
+		ScopeId:    "172.16.11.0",
+		StartRange: "172.16.11.10",
+		EndRange:   "172.16.11.250",
+	}
+	reservations := []*model.DhcpReservation{
+		{ScopeId: "172.16.11.0", IpV4: "172.16.11.100", Name: "printer"},
+	}
+	exclusions := []*model.DhcpExclusion{
+		{ScopeId: "172.16.11.0", StartRange: "172.16.11.10", EndRange: "172.16.11.19"},
+	}
+
+	cases := []struct {
+		start string
+		end   string
+		valid bool
+	}{
+		{"172.16.11.20", "172.16.11.29", true},
+		{"172.16.11.29", "172.16.11.20", false},
+		{"172.16.11.1", "172.16.11.9", false},
+		{"172.16.11.240", "172.16.11.254", false},
+		{"172.16.11.90", "172.16.11.110", false},
+		{"172.16.11.15", "172.16.11.25", false},
+		{"172.16.11.x", "172.16.11.25", false},
+	}
+	for i := 0; i < len(cases); i++ {
+		item := cases[i]
+		err := dhcp.checkExclusion(&model.DhcpExclusion{
+			ScopeId:    scope.ScopeId,
+			StartRange: item.start,
+			EndRange:   item.end,
+		}, scope, reservations, exclusions)
+		if (err == nil) != item.valid {
+			t.Errorf("%s-%s: expect valid=%v, error: %v", item.start, item.end, item.valid, err)
+		}
+	}
+}
+
+func TestDhcp_getScopeLeases(t *testing.T) {
+	dhcp := &Dhcp{Concurrency: 2}
+	scopes := []string{"172.16.10.0", "", "172.16.11.0", "172.16.12.0"}
+	list := &model.DhcpLeaseList{}
+	list.Scopes = dhcp.getScopeLeases(model.DhcpFamilyIpV4, scopes, func(scopeId string) ([]*model.DhcpLease, error) {
+		if scopeId == "172.16.11.0" {
+			return nil, fmt.Errorf("access denied")
+		}
+		return []*model.DhcpLease{{ScopeId: scopeId}}, nil
+	})
+	if len(list.Scopes) != 3 {
+		t.Fatal("count:", len(list.Scopes))
+	}
+	for i := 0; i < len(list.Scopes); i++ {
+		scope := list.Scopes[i]
+		if scope.ScopeId == "172.16.11.0" {
+			if scope.Error != "access denied" || len(scope.Leases) != 0 {
+				t.Error("failed scope:", fmtItem(scope))
+			}
+		} else if len(scope.Leases) != 1 || scope.Leases[0].ScopeId != scope.ScopeId {
+			t.Error("scope:", fmtItem(scope))
+		}
+	}
+
+	leases, errs := dhcp.FlattenLeases(list)
+	if len(errs) != 1 || errs[0] != "scope 172.16.11.0: access denied" || len(leases) != 2 {
+		t.Error("flatten:", errs, len(leases))
+	}
+	list.Scopes[1].Error = ""
+	leases, errs = dhcp.FlattenLeases(list)
+	if len(errs) != 0 || len(leases) != 2 {
+		t.Error("flatten:", errs, len(leases))
+	}
+}
+
+func TestDhcp_getScopeStatistics(t *testing.T) {
+	output := `"ScopeId","AddressesFree","AddressesInUse","ReservedAddress","PendingOffers","PercentageInUse"
+"172.16.11.0","41","200","12","0","82.98755"
+"172.16.12.0","100","0","0","0","0"
+`
+	dhcp := &Dhcp{}
+	items := dhcp.getScopeStatistics([]byte(output))
+	if len(items) != 2 {
+		t.Fatal("count: expect 2, actual", len(items))
+	}
+
+	item := items[0]
+	if item.Total != 241 || item.InUse != 200 || item.Free != 41 || item.Reserved != 12 {
+		t.Error("statistics:", fmtItem(item))
+	}
+	if item.Percentage != 82.99 {
+		t.Error("percentage:", item.Percentage)
+	}
+}
+
+func TestDhcp_getV6Leases(t *testing.T) {
+	output := `"Prefix","IPAddress","ClientDuid","Iaid","HostName","LeaseExpiryTime","AddressType","Description"
+"2001:db8:1::","2001:db8:1::1f","00-01-00-01-26-6a-2b-3c-90-94-97-8b-f5-f8","144741523","pc-01.example.com","2020-10-19T08:30:00","IANA",""
+`
+	dhcp := &Dhcp{}
+	items := dhcp.getV6Leases([]byte(output))
+	if len(items) != 1 {
+		t.Fatal("count: expect 1, actual", len(items))
+	}
+
+	item := items[0]
+	if item.Family != model.DhcpFamilyIpV6 || item.IpV6 != "2001:db8:1::1f" || item.IpV4 != "" {
+		t.Error("address:", fmtItem(item))
+	}
+	if item.Iaid != "144741523" || item.ClientDuid != "00-01-00-01-26-6a-2b-3c-90-94-97-8b-f5-f8" {
+		t.Error("client:", fmtItem(item))
+	}
+}
+
+func TestDhcp_getFailovers(t *testing.T) {
+	output := `"Name","PartnerServer","Mode","State","ServerRole","LoadBalancePercent","ReservePercent","MaxClientLeadTime","AutoStateTransition","StateSwitchInterval","ScopeId"
+"dhcp1-dhcp2","dhcp2.example.com","LoadBalance","Normal","","50","0","01:00:00","False","","172.16.11.0,172.16.12.0"
+"standby","dhcp3.example.com","HotStandby","CommunicationInterrupted","Active","0","5","01:00:00","True","01:00:00",""
+`
+	dhcp := &Dhcp{}
+	items := dhcp.getFailovers([]byte(output))
+	if len(items) != 2 {
+		t.Fatal("count: expect 2, actual", len(items))
+	}
+
+	item := items[0]
+	if item.Mode != "LoadBalance" || item.LoadBalancePercent != 50 || item.AutoStateTransition {
+		t.Error("mode:", fmtItem(item))
+	}
+	if len(item.ScopeIds) != 2 || item.ScopeIds[1] != "172.16.12.0" {
+		t.Error("scopes:", fmtItem(item))
+	}
+	item = items[1]
+	if item.ServerRole != "Active" || item.ReservePercent != 5 || !item.AutoStateTransition || len(item.ScopeIds) != 0 {
+		t.Error("standby:", fmtItem(item))
+	}
+}
+
+func TestDhcp_diffFilters(t *testing.T) {
+	dhcp := &Dhcp{}
+	content := `allow,address,comment
+true,00:1c:23:20:af:4a,laptop
+deny,00-1C-23-20-AF-4B,phone
+allow,00-1C-23-20-AF-4C,printer
+`
+	targets, err := dhcp.ParseFilters(model.DhcpFilterFormatCsv, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if targets[0].Address != "00-1C-23-20-AF-4A" {
+		t.Error("address not normalized:", targets[0].Address)
+	}
+
+	filters := []*model.DhcpFilter{
+		{Allow: true, Address: "00-1C-23-20-AF-4A", Comment: "laptop"},
+		{Allow: true, Address: "00-1C-23-20-AF-4B", Comment: "phone"},
+		{Allow: true, Address: "00-1C-23-20-AF-4D", Comment: "old"},
+	}
+	result := dhcp.diffFilters(filters, targets, true)
+	if result.Unchanged != 1 || len(result.Added) != 1 || len(result.Updated) != 1 || len(result.Removed) != 1 {
+		t.Fatal("result:", fmtItem(result))
+	}
+	if result.Updated[0].Allow {
+		t.Error("updated:", fmtItem(result.Updated[0]))
+	}
+
+	result = dhcp.diffFilters(filters, targets, false)
+	if len(result.Removed) != 0 {
+		t.Error("removed:", fmtItem(result.Removed))
+	}
+
+	file, err := dhcp.formatFilters(model.DhcpFilterFormatJson, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := dhcp.ParseFilters(file.Format, file.Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != len(filters) {
+		t.Error("json count: expect", len(filters), "actual", len(items))
+	}
+
+	items, err = dhcp.ParseFilters(model.DhcpFilterFormatCsv, "allow,00-1C-23-20-AF-4A,a\n")
+	if err != nil || len(items) != 1 {
+		t.Error("headerless first row:", err, fmtItem(items))
+	}
+
+	_, err = dhcp.ParseFilters(model.DhcpFilterFormatCsv, "true,00-1C-23-20-AF-4A,a\nfalse,00:1C:23:20:AF:4A,b\n")
+	if err == nil {
+		t.Error("duplicated address should fail")
+	}
+}
+
+func TestDhcp_GetRogueReport(t *testing.T) {
+	dhcp := &Dhcp{}
+	now := time.Date(2020, 10, 19, 8, 30, 0, 0, time.Local)
+	leases := []*model.DhcpLease{
+		{IpV4: "172.16.11.19", Address: "90-94-97-8B-F5-F8", AddressState: "Active"},
+		{IpV4: "172.16.11.20", Address: "9C-B6-D0-E8-38-47", AddressState: "Active"},
+		{IpV4: "172.16.11.21", Address: "DA-A1-19-00-11-22", AddressState: "Active"},
+	}
+	filters := []*model.DhcpFilter{
+		{Allow: true, Address: "90-94-97-8B-F5-F8"},
+		{Allow: true, Address: "00-1C-23-20-AF-4A"},
+		{Allow: true, Address: "00-1C-23-20-AF-4B"},
+		{Allow: true, Address: "00-1C-23-20-AF-4C"},
+		{Allow: false, Address: "9C-B6-D0-E8-38-47"},
+	}
+	lastSeen := map[string]time.Time{
+		"00-1C-23-20-AF-4A": now.AddDate(0, 0, -2),
+		"00-1C-23-20-AF-4B": now.AddDate(0, 0, -60),
+	}
+
+	report := dhcp.GetRogueReport(leases, filters, lastSeen, 30, now)
+	if len(report.Unknown) != 1 || report.Unknown[0].IpV4 != "172.16.11.21" {
+		t.Error("unknown:", fmtItem(report.Unknown))
+	}
+	if len(report.Denied) != 1 || report.Denied[0].IpV4 != "172.16.11.20" {
+		t.Error("denied:", fmtItem(report.Denied))
+	}
+	if len(report.Idle) != 2 || report.Idle[0].Address != "00-1C-23-20-AF-4B" || report.Idle[1].LastSeen != "" {
+		t.Error("idle:", fmtItem(report.Idle))
+	}
+}
@@ -0,0 +1,187 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"time"
+)
+
+const (
+	dhcpV6LeaseFields = "Prefix,IPAddress,ClientDuid,Iaid,HostName," +
+		"@{n='LeaseExpiryTime';e={if($_.LeaseExpiryTime){$_.LeaseExpiryTime.ToString('s')}}}," +
+		"AddressType,Description"
+)
+
+func (s *Dhcp) GetV6Scopes() ([]*model.DhcpScope, error) {
+	output, err := s.runShell("Get-DhcpServerv6Scope",
+		"|", "Select", "Prefix,Name,State",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DhcpScope, 0)
+	rows := s.getCsvRows(output)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["Prefix"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpScope{
+			Family:  model.DhcpFamilyIpV6,
+			ScopeId: row["Prefix"],
+			Name:    row["Name"],
+			State:   row["State"],
+		})
+	}
+
+	return results, nil
+}
+
+func (s *Dhcp) GetV6Leases() ([]*model.DhcpLease, error) {
+	list, err := s.GetV6ScopeLeases()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.flattenLeases(list)
+}
+
+func (s *Dhcp) GetV6ScopeLeases() (*model.DhcpLeaseList, error) {
+	scopes, err := s.GetV6Scopes()
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make([]string, 0)
+	for i := 0; i < len(scopes); i++ {
+		prefixes = append(prefixes, scopes[i].ScopeId)
+	}
+
+	list := &model.DhcpLeaseList{}
+	list.Scopes = s.getScopeLeases(model.DhcpFamilyIpV6, prefixes, func(prefix string) ([]*model.DhcpLease, error) {
+		o, e := s.runShell("Get-DhcpServerv6Lease", "-Prefix", prefix,
+			"|", "Select", dhcpV6LeaseFields,
+			"|", "ConvertTo-Csv", "-NoTypeInformation")
+		if e != nil {
+			return nil, e
+		}
+		return s.getV6Leases(o), nil
+	})
+
+	return list, nil
+}
+
+func (s *Dhcp) GetV6Reservations(prefix string) ([]*model.DhcpReservation, error) {
+	if len(prefix) < 1 {
+		return nil, fmt.Errorf("prefix is empty")
+	}
+	output, err := s.runShell("Get-DhcpServerv6Reservation", "-Prefix", prefix,
+		"|", "Select", "Prefix,IPAddress,ClientDuid,Iaid,Name,Description",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DhcpReservation, 0)
+	rows := s.getCsvRows(output)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["IPAddress"]) < 1 {
+			continue
+		}
+		results = append(results, &model.DhcpReservation{
+			Family:      model.DhcpFamilyIpV6,
+			ScopeId:     row["Prefix"],
+			IpV6:        row["IPAddress"],
+			ClientDuid:  row["ClientDuid"],
+			Iaid:        row["Iaid"],
+			Name:        row["Name"],
+			Description: row["Description"],
+		})
+	}
+
+	return results, nil
+}
+
+func (s *Dhcp) AddV6Reservation(v *model.DhcpReservation) error {
+	if v == nil {
+		return fmt.Errorf("reservation is nil")
+	}
+	if len(v.ScopeId) < 1 {
+		return fmt.Errorf("prefix is empty")
+	}
+	if len(v.IpV6) < 1 {
+		return fmt.Errorf("ip address is empty")
+	}
+	if len(v.ClientDuid) < 1 {
+		return fmt.Errorf("client duid is empty")
+	}
+	if len(v.Iaid) < 1 {
+		return fmt.Errorf("iaid is empty")
+	}
+
+	args := []string{"Add-DhcpServerv6Reservation", "-Prefix", v.ScopeId,
+		"-IPAddress", v.IpV6, "-ClientDuid", v.ClientDuid, "-Iaid", v.Iaid}
+	if len(v.Name) > 0 {
+		args = append(args, "-Name", s.quoteValues(v.Name))
+	}
+	if len(v.Description) > 0 {
+		args = append(args, "-Description", s.quoteValues(v.Description))
+	}
+
+	_, err := s.runShell(args...)
+	return err
+}
+
+func (s *Dhcp) DeleteV6Reservation(ipV6 string) error {
+	if len(ipV6) < 1 {
+		return fmt.Errorf("ip address is empty")
+	}
+
+	_, err := s.runShell("Remove-DhcpServerv6Reservation", "-IPAddress", ipV6, "-Confirm:$false")
+	return err
+}
+
+func (s *Dhcp) getV6Leases(text []byte) []*model.DhcpLease {
+	results := make([]*model.DhcpLease, 0)
+	if len(text) < 1 {
+		return results
+	}
+	/*
+		"Prefix","IPAddress","ClientDuid","Iaid","HostName","LeaseExpiryTime","AddressType","Description"
+		"2001:db8:1::","2001:db8:1::1f","00-01-00-01-26-6a-2b-3c-90-94-97-8b-f5-f8","144741523","pc-01.example.com","2020-10-19T08:30:00","IANA",""
+	*/
+
+	rows := s.getCsvRows(text)
+	c := len(rows)
+	for i := 0; i < c; i++ {
+		row := rows[i]
+		if len(row["IPAddress"]) < 1 {
+			continue
+		}
+
+		lease := &model.DhcpLease{
+			Family:       model.DhcpFamilyIpV6,
+			ScopeId:      row["Prefix"],
+			IpV6:         row["IPAddress"],
+			ClientDuid:   row["ClientDuid"],
+			Iaid:         row["Iaid"],
+			HostName:     row["HostName"],
+			AddressState: row["AddressType"],
+			Description:  row["Description"],
+		}
+		expiry := row["LeaseExpiryTime"]
+		if len(expiry) > 0 {
+			t, err := time.ParseInLocation("2006-01-02T15:04:05", expiry, time.Local)
+			if err == nil {
+				lease.LeaseExpiryTime = t.Format("2006-01-02 15:04:05")
+			}
+		}
+
+		results = append(results, lease)
+	}
+
+	return results
+}
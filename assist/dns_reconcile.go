@@ -0,0 +1,336 @@
+package assist
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reconcile joins active IPv4 leases with the A records of the zones, records
+// maps zone name to its records; unqualified lease host names belong to the
+// zone already holding that name, or the first zone otherwise.
+func (s *Dns) Reconcile(zones []string, records map[string][]*model.DnsRecord, leases []*model.DhcpLease, scopes []*model.DhcpScope, now time.Time) *model.DnsReconcileReport {
+	report := &model.DnsReconcileReport{
+		CheckTime: now.Format("2006-01-02 15:04:05"),
+		ZoneNames: zones,
+		Stale:     make([]*model.DnsReconcileItem, 0),
+		Missing:   make([]*model.DnsReconcileItem, 0),
+		Orphan:    make([]*model.DnsReconcileItem, 0),
+	}
+	if len(zones) < 1 {
+		return report
+	}
+
+	named := make(map[string][]*model.DnsRecord)
+	for i := 0; i < len(zones); i++ {
+		items := records[zones[i]]
+		for j := 0; j < len(items); j++ {
+			key := s.reconcileKey(zones[i], items[j].Name)
+			named[key] = append(named[key], items[j])
+		}
+	}
+
+	leased := make(map[string]bool)
+	hosts := make(map[string][]*model.DhcpLease)
+	hostKeys := make([]string, 0)
+	for i := 0; i < len(leases); i++ {
+		lease := leases[i]
+		if len(lease.IpV4) < 1 || !strings.HasPrefix(lease.AddressState, "Active") {
+			continue
+		}
+		leased[lease.IpV4] = true
+
+		zone, name := s.reconcileZone(zones, named, lease.HostName)
+		if len(name) < 1 {
+			continue
+		}
+		key := s.reconcileKey(zone, name)
+		if _, ok := hosts[key]; !ok {
+			hostKeys = append(hostKeys, key)
+		}
+		hosts[key] = append(hosts[key], lease)
+	}
+
+	for i := 0; i < len(zones); i++ {
+		zone := zones[i]
+		items := records[zone]
+		for j := 0; j < len(items); j++ {
+			record := items[j]
+			key := s.reconcileKey(zone, record.Name)
+			hostLeases, ok := hosts[key]
+			if ok {
+				// static addresses outside the scopes, e.g. of a second adapter, are kept
+				if s.reconcileLease(hostLeases, record.Data) != nil || !s.reconcileInRange(scopes, record.Data) {
+					continue
+				}
+				item := &model.DnsReconcileItem{
+					Kind:     model.DnsReconcileKindStale,
+					Action:   model.DnsReconcileActionUpdate,
+					ZoneName: zone,
+					Name:     record.Name,
+					Data:     record.Data,
+					IpV4:     hostLeases[0].IpV4,
+					Address:  hostLeases[0].Address,
+					HostName: hostLeases[0].HostName,
+				}
+				if s.reconcileMatched(named[key], hostLeases) {
+					item.Action = model.DnsReconcileActionDelete
+				}
+				report.Stale = append(report.Stale, item)
+			} else if !leased[record.Data] && s.reconcileInRange(scopes, record.Data) {
+				report.Orphan = append(report.Orphan, &model.DnsReconcileItem{
+					Kind:     model.DnsReconcileKindOrphan,
+					Action:   model.DnsReconcileActionDelete,
+					ZoneName: zone,
+					Name:     record.Name,
+					Data:     record.Data,
+				})
+			}
+		}
+	}
+
+	for i := 0; i < len(hostKeys); i++ {
+		key := hostKeys[i]
+		if s.reconcileMatched(named[key], hosts[key]) || s.reconcileAnyInRange(scopes, named[key]) {
+			continue
+		}
+		lease := hosts[key][0]
+		zone, name := s.reconcileZone(zones, named, lease.HostName)
+		report.Missing = append(report.Missing, &model.DnsReconcileItem{
+			Kind:     model.DnsReconcileKindMissing,
+			Action:   model.DnsReconcileActionAdd,
+			ZoneName: zone,
+			Name:     name,
+			IpV4:     lease.IpV4,
+			Address:  lease.Address,
+			HostName: lease.HostName,
+		})
+	}
+
+	s.identifyReconcileItems(report.Stale)
+	s.identifyReconcileItems(report.Missing)
+	s.identifyReconcileItems(report.Orphan)
+	s.sortReconcileItems(report.Stale)
+	s.sortReconcileItems(report.Missing)
+	s.sortReconcileItems(report.Orphan)
+
+	return report
+}
+
+// SelectReconcile picks the items of the report with the given ids, and
+// returns the ids no longer found in the report.
+func (s *Dns) SelectReconcile(report *model.DnsReconcileReport, ids []string) ([]*model.DnsReconcileItem, []string) {
+	items := make(map[string]*model.DnsReconcileItem)
+	groups := [][]*model.DnsReconcileItem{report.Stale, report.Missing, report.Orphan}
+	for i := 0; i < len(groups); i++ {
+		for j := 0; j < len(groups[i]); j++ {
+			items[groups[i][j].Id] = groups[i][j]
+		}
+	}
+
+	results := make([]*model.DnsReconcileItem, 0)
+	missing := make([]string, 0)
+	selected := make(map[string]bool)
+	for i := 0; i < len(ids); i++ {
+		id := ids[i]
+		if selected[id] {
+			continue
+		}
+		selected[id] = true
+
+		item, ok := items[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		results = append(results, item)
+	}
+
+	return results, missing
+}
+
+// FixReconcile applies the items in their zones. Once the lease address of a
+// host has been added, further stale records of that host are only deleted,
+// so a host with several stale records gets a single new record.
+func (s *Dns) FixReconcile(items []*model.DnsReconcileItem) *model.DnsReconcileFixResult {
+	result := &model.DnsReconcileFixResult{
+		Applied: make([]*model.DnsReconcileItem, 0),
+		Errors:  make([]string, 0),
+	}
+
+	added := make(map[string]bool)
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		key := ""
+		if item.Action == model.DnsReconcileActionAdd || item.Action == model.DnsReconcileActionUpdate {
+			key = fmt.Sprintf("%s/%s", s.reconcileKey(item.ZoneName, item.Name), item.IpV4)
+			if added[key] {
+				if item.Action == model.DnsReconcileActionAdd {
+					continue
+				}
+				copied := *item
+				copied.Action = model.DnsReconcileActionDelete
+				item = &copied
+				key = ""
+			}
+		}
+
+		dns := &Dns{
+			ZoneName: item.ZoneName,
+		}
+		err := dns.ApplyReconcile(item)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s %s.%s: %v", item.Action, item.Name, item.ZoneName, err))
+			continue
+		}
+		if len(key) > 0 {
+			added[key] = true
+		}
+		result.Applied = append(result.Applied, item)
+	}
+
+	return result
+}
+
+func (s *Dns) ApplyReconcile(item *model.DnsReconcileItem) error {
+	if item == nil {
+		return fmt.Errorf("item is nil")
+	}
+	if len(item.Name) < 1 {
+		return fmt.Errorf("record name is empty")
+	}
+
+	switch item.Action {
+	case model.DnsReconcileActionAdd:
+		if len(item.IpV4) < 1 {
+			return fmt.Errorf("lease address of '%s' is empty", item.Name)
+		}
+		return s.AddRecord(item.Name, item.IpV4)
+	case model.DnsReconcileActionDelete:
+		if len(item.Data) < 1 {
+			return fmt.Errorf("record data of '%s' is empty", item.Name)
+		}
+		return s.DeleteRecord(item.Name, item.Data)
+	case model.DnsReconcileActionUpdate:
+		if len(item.Data) < 1 || len(item.IpV4) < 1 {
+			return fmt.Errorf("record data or lease address of '%s' is empty", item.Name)
+		}
+		err := s.AddRecord(item.Name, item.IpV4)
+		if err != nil {
+			return err
+		}
+		return s.DeleteRecord(item.Name, item.Data)
+	default:
+		return fmt.Errorf("action '%s' not supported", item.Action)
+	}
+}
+
+func (s *Dns) reconcileKey(zone, name string) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s", zone, name))
+}
+
+func (s *Dns) reconcileZone(zones []string, named map[string][]*model.DnsRecord, hostName string) (string, string) {
+	host := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostName), "."))
+	if len(host) < 1 {
+		return "", ""
+	}
+
+	zone := ""
+	name := ""
+	for i := 0; i < len(zones); i++ {
+		suffix := "." + strings.ToLower(zones[i])
+		if strings.HasSuffix(host, suffix) && len(zones[i]) > len(zone) {
+			zone = zones[i]
+			name = strings.TrimSuffix(host, suffix)
+		}
+	}
+	if len(zone) > 0 {
+		return zone, name
+	}
+	if strings.Contains(host, ".") {
+		return "", ""
+	}
+
+	for i := 0; i < len(zones); i++ {
+		if _, ok := named[s.reconcileKey(zones[i], host)]; ok {
+			return zones[i], host
+		}
+	}
+
+	return zones[0], host
+}
+
+func (s *Dns) reconcileLease(leases []*model.DhcpLease, ip string) *model.DhcpLease {
+	for i := 0; i < len(leases); i++ {
+		if leases[i].IpV4 == ip {
+			return leases[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Dns) reconcileMatched(records []*model.DnsRecord, leases []*model.DhcpLease) bool {
+	for i := 0; i < len(records); i++ {
+		if s.reconcileLease(leases, records[i].Data) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Dns) reconcileAnyInRange(scopes []*model.DhcpScope, records []*model.DnsRecord) bool {
+	for i := 0; i < len(records); i++ {
+		if s.reconcileInRange(scopes, records[i].Data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Dns) reconcileInRange(scopes []*model.DhcpScope, ip string) bool {
+	value, ok := s.reconcileIp(ip)
+	if !ok {
+		return false
+	}
+	for i := 0; i < len(scopes); i++ {
+		start, sok := s.reconcileIp(scopes[i].StartRange)
+		end, eok := s.reconcileIp(scopes[i].EndRange)
+		if sok && eok && value >= start && value <= end {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Dns) reconcileIp(v string) (uint32, bool) {
+	ip := net.ParseIP(strings.TrimSpace(v)).To4()
+	if ip == nil {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint32(ip), true
+}
+
+func (s *Dns) identifyReconcileItems(items []*model.DnsReconcileItem) {
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		item.Id = s.uniqueId(item.Kind, fmt.Sprintf("%s/%s/%s/%s", item.ZoneName, item.Name, item.Data, item.IpV4))
+	}
+}
+
+func (s *Dns) sortReconcileItems(items []*model.DnsReconcileItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ZoneName != items[j].ZoneName {
+			return items[i].ZoneName < items[j].ZoneName
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -1,7 +1,9 @@
 package assist
 
 import (
+	"github.com/csby/gwin/model"
 	"testing"
+	"time"
 )
 
 func TestDns_getRecords(t *testing.T) {
@@ -24,3 +26,77 @@ func TestDns_getRecords(t *testing.T) {
 		t.Logf("%3d %14s %s", i+1, item.Name, item.Data)
 	}
 }
+
+func TestDns_Reconcile(t *testing.T) {
+	dns := &Dns{}
+	zones := []string{"example.com"}
+	records := map[string][]*model.DnsRecord{
+		"example.com": {
+			{Name: "pc-01", Data: "172.16.11.50"},
+			{Name: "pc-02", Data: "172.16.11.21"},
+			{Name: "pc-03", Data: "172.16.11.22"},
+			{Name: "server", Data: "172.16.11.5"},
+			{Name: "pc-06", Data: "10.0.0.6"},
+		},
+	}
+	leases := []*model.DhcpLease{
+		{IpV4: "172.16.11.20", HostName: "PC-01.example.com", AddressState: "Active"},
+		{IpV4: "172.16.11.21", HostName: "pc-02", AddressState: "Active"},
+		{IpV4: "172.16.11.23", HostName: "pc-04.example.com", AddressState: "ActiveReservation"},
+		{IpV4: "172.16.11.24", HostName: "pc-05.other.com", AddressState: "Active"},
+		{IpV4: "172.16.11.22", HostName: "pc-03.example.com", AddressState: "Expired"},
+		{IpV4: "172.16.11.26", HostName: "pc-06", AddressState: "Active"},
+	}
+	scopes := []*model.DhcpScope{
+		{ScopeId: "172.16.11.0", StartRange: "172.16.11.10", EndRange: "172.16.11.250"},
+	}
+
+	report := dns.Reconcile(zones, records, leases, scopes, time.Now())
+	if len(report.Stale) != 1 || report.Stale[0].Name != "pc-01" || report.Stale[0].IpV4 != "172.16.11.20" ||
+		report.Stale[0].Action != model.DnsReconcileActionUpdate {
+		t.Error("stale:", fmtItem(report.Stale))
+	}
+	if len(report.Missing) != 2 || report.Missing[0].Name != "pc-04" || report.Missing[0].ZoneName != "example.com" ||
+		report.Missing[1].Name != "pc-06" {
+		t.Error("missing:", fmtItem(report.Missing))
+	}
+	if len(report.Orphan) != 1 || report.Orphan[0].Name != "pc-03" {
+		t.Error("orphan:", fmtItem(report.Orphan))
+	}
+}
+
+func TestDns_SelectReconcile(t *testing.T) {
+	dns := &Dns{}
+	zones := []string{"example.com"}
+	records := map[string][]*model.DnsRecord{
+		"example.com": {
+			{Name: "pc-01", Data: "172.16.11.50"},
+			{Name: "pc-01", Data: "172.16.11.51"},
+		},
+	}
+	leases := []*model.DhcpLease{
+		{IpV4: "172.16.11.20", HostName: "pc-01.example.com", AddressState: "Active"},
+	}
+
+	scopes := []*model.DhcpScope{
+		{ScopeId: "172.16.11.0", StartRange: "172.16.11.10", EndRange: "172.16.11.250"},
+	}
+
+	report := dns.Reconcile(zones, records, leases, scopes, time.Now())
+	if len(report.Stale) != 2 || report.Stale[0].Id == report.Stale[1].Id {
+		t.Fatal("stale:", fmtItem(report.Stale))
+	}
+	again := dns.Reconcile(zones, records, leases, scopes, time.Now())
+	if again.Stale[0].Id != report.Stale[0].Id {
+		t.Error("id should be stable between reports")
+	}
+
+	ids := []string{report.Stale[1].Id, report.Stale[1].Id, "unknown"}
+	items, missing := dns.SelectReconcile(again, ids)
+	if len(items) != 1 || items[0] != again.Stale[1] {
+		t.Error("items:", fmtItem(items))
+	}
+	if len(missing) != 1 || missing[0] != "unknown" {
+		t.Error("missing:", missing)
+	}
+}
@@ -61,6 +61,210 @@ func (s *MsAd) GetAllUsers() ([]*model.MsAdUser, error) {
 	return items, nil
 }
 
+func (s *MsAd) GetAllGroups() ([]*model.MsAdGroup, error) {
+	l, err := s.open(true)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	filter := fmt.Sprintf("(&(objectCategory=%s)(objectClass=%s))", "Group", "group")
+	attributes := []string{"dn", "objectSid", "sAMAccountName", "name", "description", "member"}
+	request := ldap.NewSearchRequest(
+		s.Base,
+		ldap.ScopeWholeSubtree, ldap.DerefAlways, 0, 0, false,
+		filter,
+		attributes,
+		nil)
+
+	rs, err := l.Search(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(model.MsAdGroupCollection, 0)
+	c := len(rs.Entries)
+	for i := 0; i < c; i++ {
+		entry := rs.Entries[i]
+		if entry == nil {
+			continue
+		}
+
+		item := &model.MsAdGroup{}
+		item.Account = entry.GetAttributeValue("sAMAccountName")
+		item.Id = s.decodeSID(entry.GetRawAttributeValue("objectSid"))
+		item.Name = entry.GetAttributeValue("name")
+		if len(item.Name) < 1 {
+			item.Name = item.Account
+		}
+		item.Description = entry.GetAttributeValue("description")
+		item.Members = len(entry.GetAttributeValues("member"))
+
+		items = append(items, item)
+	}
+
+	sort.Sort(items)
+	return items, nil
+}
+
+// GetAccountGroups returns the groups of the account including nested ones and the primary group,
+// the account is identified by SID.
+func (s *MsAd) GetAccountGroups(sid string) ([]*model.MsAdGroup, error) {
+	l, err := s.open(true)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	entry, err := s.searchSID(l, sid, "dn", "primaryGroupID")
+	if err != nil {
+		return nil, err
+	}
+	dns := []string{entry.DN}
+	primaryGroupId := entry.GetAttributeValue("primaryGroupID")
+	index := strings.LastIndex(sid, "-")
+	if len(primaryGroupId) > 0 && index > 0 {
+		primary, pe := s.searchSID(l, sid[:index+1]+primaryGroupId, "dn")
+		if pe == nil {
+			dns = append(dns, primary.DN)
+		}
+	}
+
+	groups := make(map[string]*model.MsAdGroup)
+	for i := 0; i < len(dns); i++ {
+		if i > 0 {
+			// the primary group itself
+			filter := fmt.Sprintf("(&(objectClass=group)(distinguishedName=%s))", ldap.EscapeFilter(dns[i]))
+			err = s.searchGroups(l, filter, groups)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// LDAP_MATCHING_RULE_IN_CHAIN expands nested membership
+		filter := fmt.Sprintf("(&(objectClass=group)(member:1.2.840.113556.1.4.1941:=%s))", ldap.EscapeFilter(dns[i]))
+		err = s.searchGroups(l, filter, groups)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items := make(model.MsAdGroupCollection, 0, len(groups))
+	for _, group := range groups {
+		items = append(items, group)
+	}
+
+	sort.Sort(items)
+	return items, nil
+}
+
+// GetGroupIds returns which of the SIDs are groups, only the given SIDs are looked up.
+func (s *MsAd) GetGroupIds(sids []string) (map[string]bool, error) {
+	groups := make(map[string]bool)
+	if len(sids) < 1 {
+		return groups, nil
+	}
+	l, err := s.open(true)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	// keeps the filter in a reasonable size
+	size := 100
+	for start := 0; start < len(sids); start += size {
+		end := start + size
+		if end > len(sids) {
+			end = len(sids)
+		}
+		filter := &strings.Builder{}
+		filter.WriteString("(&(objectClass=group)(|")
+		for i := start; i < end; i++ {
+			filter.WriteString(fmt.Sprintf("(objectSid=%s)", ldap.EscapeFilter(sids[i])))
+		}
+		filter.WriteString("))")
+
+		request := ldap.NewSearchRequest(
+			s.Base,
+			ldap.ScopeWholeSubtree, ldap.DerefAlways, 0, 0, false,
+			filter.String(),
+			[]string{"objectSid"},
+			nil)
+		rs, se := l.Search(request)
+		if se != nil {
+			return nil, se
+		}
+		for i := 0; i < len(rs.Entries); i++ {
+			entry := rs.Entries[i]
+			if entry == nil {
+				continue
+			}
+			groups[s.decodeSID(entry.GetRawAttributeValue("objectSid"))] = true
+		}
+	}
+
+	return groups, nil
+}
+
+func (s *MsAd) searchSID(l *ldap.Conn, sid string, attributes ...string) (*ldap.Entry, error) {
+	request := ldap.NewSearchRequest(
+		s.Base,
+		ldap.ScopeWholeSubtree, ldap.DerefAlways, 1, 0, false,
+		fmt.Sprintf("(objectSid=%s)", ldap.EscapeFilter(sid)),
+		attributes,
+		nil)
+	rs, err := l.Search(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(rs.Entries) < 1 || rs.Entries[0] == nil {
+		return nil, fmt.Errorf("account '%s' not found", sid)
+	}
+
+	return rs.Entries[0], nil
+}
+
+func (s *MsAd) searchGroups(l *ldap.Conn, filter string, groups map[string]*model.MsAdGroup) error {
+	request := ldap.NewSearchRequest(
+		s.Base,
+		ldap.ScopeWholeSubtree, ldap.DerefAlways, 0, 0, false,
+		filter,
+		[]string{"dn", "objectSid", "sAMAccountName", "name", "description"},
+		nil)
+	rs, err := l.Search(request)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rs.Entries); i++ {
+		entry := rs.Entries[i]
+		if entry == nil {
+			continue
+		}
+		item := &model.MsAdGroup{}
+		item.Account = entry.GetAttributeValue("sAMAccountName")
+		item.Id = s.decodeSID(entry.GetRawAttributeValue("objectSid"))
+		item.Name = entry.GetAttributeValue("name")
+		if len(item.Name) < 1 {
+			item.Name = item.Account
+		}
+		item.Description = entry.GetAttributeValue("description")
+		groups[item.Id] = item
+	}
+
+	return nil
+}
+
+// IsWellKnownGroup reports whether the SID is a well-known group,
+// e.g. Everyone, Authenticated Users or a BUILTIN group.
+func (s *MsAd) IsWellKnownGroup(sid string) bool {
+	switch sid {
+	case "S-1-1-0", "S-1-5-4", "S-1-5-11":
+		return true
+	}
+
+	return strings.HasPrefix(sid, "S-1-5-32-")
+}
+
 func (s *MsAd) open(bind bool) (*ldap.Conn, error) {
 	var (
 		conn *ldap.Conn
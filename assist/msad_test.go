@@ -28,3 +28,19 @@ func TestMsAd_GetAllUsers(t *testing.T) {
 		t.Logf("%3d  %20s  %s", i+1, item.Name, item.Id)
 	}
 }
+
+func TestMsAd_IsWellKnownGroup(t *testing.T) {
+	ad := &MsAd{}
+	groups := map[string]bool{
+		"S-1-1-0":      true,
+		"S-1-5-11":     true,
+		"S-1-5-32-545": true,
+		"S-1-5-21-1114322273-403004966-1807125474-1104": false,
+		"": false,
+	}
+	for sid, expect := range groups {
+		if ad.IsWellKnownGroup(sid) != expect {
+			t.Errorf("'%s': expect %v", sid, expect)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package assist
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const ouiEmbeddedSource = "embedded"
+
+// oui.csv is the IEEE registry (standards-oui.ieee.org/oui/oui.csv), replace it to update the built-in vendors
+//
+//go:embed oui.csv
+var ouiEmbedded []byte
+
+var defaultOui = newEmbeddedOui()
+
+func newEmbeddedOui() *Oui {
+	oui := &Oui{}
+	vendors, err := oui.parse(bytes.NewReader(ouiEmbedded))
+	if err == nil && len(vendors) > 0 {
+		oui.vendors = vendors
+		oui.source = ouiEmbeddedSource
+	}
+
+	return oui
+}
+
+type Oui struct {
+	sync.RWMutex
+
+	vendors map[string]string
+	source  string
+}
+
+func (s *Oui) Load(filePath string) (int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	vendors, err := s.parse(file)
+	if err != nil {
+		return 0, err
+	}
+	if len(vendors) < 1 {
+		return 0, fmt.Errorf("no vendor found in '%s'", filePath)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.vendors = vendors
+	s.source = filePath
+
+	return len(vendors), nil
+}
+
+func (s *Oui) Source() string {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.vendors == nil {
+		return ""
+	}
+
+	return s.source
+}
+
+func (s *Oui) Count() int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return len(s.vendors)
+}
+
+func (s *Oui) Vendor(address string) string {
+	hex := s.hex(address)
+	if len(hex) < 6 {
+		return ""
+	}
+
+	s.RLock()
+	vendors := s.vendors
+	s.RUnlock()
+	if vendors == nil {
+		return ""
+	}
+
+	// MA-S (36 bits) and MA-M (28 bits) blocks take precedence over MA-L (24 bits)
+	lengths := []int{9, 7, 6}
+	for i := 0; i < len(lengths); i++ {
+		if len(hex) < lengths[i] {
+			continue
+		}
+		vendor, ok := vendors[hex[:lengths[i]]]
+		if ok {
+			return vendor
+		}
+	}
+
+	return ""
+}
+
+func (s *Oui) Randomized(address string) bool {
+	hex := s.hex(address)
+	if len(hex) < 2 {
+		return false
+	}
+
+	var first byte
+	_, err := fmt.Sscanf(hex[:2], "%02X", &first)
+	if err != nil {
+		return false
+	}
+
+	// locally administered bit, set by phones and laptops using private addresses
+	return first&0x02 != 0
+}
+
+func (s *Oui) hex(address string) string {
+	sb := &strings.Builder{}
+	v := strings.ToUpper(address)
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if (c >= '0' && c <= '9') || (c >= 'A' && c <= 'F') {
+			sb.WriteByte(c)
+		} else if c != '-' && c != ':' && c != '.' {
+			return ""
+		}
+	}
+
+	return sb.String()
+}
+
+func (s *Oui) parse(reader io.Reader) (map[string]string, error) {
+	vendors := make(map[string]string)
+	buf := bufio.NewReader(reader)
+	head, err := buf.Peek(8)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if strings.HasPrefix(string(head), "Registry") {
+		// Registry,Assignment,Organization Name,Organization Address
+		// MA-L,005056,"VMware, Inc.",3401 Hillview Avenue PALO ALTO CA US 94304
+		r := csv.NewReader(buf)
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(records); i++ {
+			record := records[i]
+			if len(record) < 3 {
+				continue
+			}
+			hex := s.hex(record[1])
+			if len(hex) < 6 {
+				continue
+			}
+			vendors[hex] = strings.TrimSpace(record[2])
+		}
+
+		return vendors, nil
+	}
+
+	// 00-50-56   (hex)		VMware, Inc.
+	for {
+		line, err := buf.ReadString('\n')
+		index := strings.Index(line, "(hex)")
+		if index > 0 {
+			hex := s.hex(strings.TrimSpace(line[:index]))
+			vendor := strings.TrimSpace(line[index+5:])
+			if len(hex) == 6 && len(vendor) > 0 {
+				vendors[hex] = vendor
+			}
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return vendors, nil
+}
@@ -0,0 +1,71 @@
+package assist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOui_parse(t *testing.T) {
+	oui := &Oui{}
+	txt := `OUI/MA-L                                                    Organization
+company_id                                                  Organization
+                                                            Address
+
+00-50-56   (hex)		VMware, Inc.
+005056     (base 16)		VMware, Inc.
+				3401 Hillview Avenue
+				PALO ALTO  CA  94304
+				US
+
+B8-27-EB   (hex)		Raspberry Pi Foundation
+B827EB     (base 16)		Raspberry Pi Foundation
+`
+	vendors, err := oui.parse(strings.NewReader(txt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vendors) != 2 || vendors["005056"] != "VMware, Inc." {
+		t.Error("txt:", vendors)
+	}
+
+	csv := `Registry,Assignment,Organization Name,Organization Address
+MA-L,001C23,Dell Inc.,One Dell Way Round Rock TX US 78682
+MA-M,001C237,"Example, Ltd.",Somewhere
+`
+	vendors, err = oui.parse(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vendors) != 2 || vendors["001C237"] != "Example, Ltd." {
+		t.Error("csv:", vendors)
+	}
+
+	oui.vendors = vendors
+	if v := oui.Vendor("00-1C-23-70-AF-4A"); v != "Example, Ltd." {
+		t.Error("ma-m vendor:", v)
+	}
+	if v := oui.Vendor("00:1c:23:20:af:4a"); v != "Dell Inc." {
+		t.Error("ma-l vendor:", v)
+	}
+}
+
+func TestOui_Randomized(t *testing.T) {
+	oui := &Oui{}
+	cases := map[string]bool{
+		"00-1C-23-20-AF-4A": false,
+		"DA-A1-19-00-11-22": true,
+		"3e:22:fb:01:02:03": true,
+		"invalid":           false,
+	}
+	for address, randomized := range cases {
+		if oui.Randomized(address) != randomized {
+			t.Errorf("%s: expect %v", address, randomized)
+		}
+	}
+	if oui.Vendor("00-50-56-C0-00-08") != "" {
+		t.Error("vendor should be empty before the database is loaded")
+	}
+	if defaultOui.Source() != ouiEmbeddedSource || defaultOui.Vendor("00-50-56-C0-00-08") != "VMware, Inc." {
+		t.Error("embedded:", defaultOui.Source(), defaultOui.Count())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/csby/gwin/model"
 	"io"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -61,17 +62,25 @@ func (s *Svn) GetRepositories(folder bool) ([]*model.SvnRepositoryItem, error) {
 	return items, nil
 }
 
-func (s *Svn) NewRepository(repository string) error {
+func (s *Svn) NewRepository(repository string, folders []string) error {
 	if len(repository) < 1 {
 		return fmt.Errorf("repository is empty")
 	}
+	admin := &SvnAdmin{}
+	paths, err := admin.Folders(folders)
+	if err != nil {
+		return err
+	}
 
-	_, err := s.runCmd("New-SvnRepository", repository)
+	_, err = s.runCmd("New-SvnRepository", repository)
 	if err != nil {
 		return err
 	}
+	if len(paths) < 1 {
+		return nil
+	}
 
-	_, err = s.runCmd("New-SvnRepositoryItem", repository, "-Path", "/branches,/tags,/trunk", "-Type", "Folder")
+	_, err = s.runCmd("New-SvnRepositoryItem", repository, "-Path", strings.Join(paths, ","), "-Type", "Folder")
 	if err != nil {
 		return err
 	}
@@ -92,6 +101,46 @@ func (s *Svn) GetRepositoryFolders(repository, path string, recursive bool) ([]*
 	return parent.Children, nil
 }
 
+func (s *Svn) GetRepositoryPath(repository string) (string, error) {
+	if len(repository) < 1 {
+		return "", fmt.Errorf("repository is empty")
+	}
+	if strings.ContainsAny(repository, "/\\:") || repository == "." || repository == ".." {
+		return "", fmt.Errorf("repository '%s' invalid", repository)
+	}
+	output, err := s.runCmd("(Get-SvnServerConfiguration).RepositoriesRoot")
+	if err != nil {
+		return "", err
+	}
+	root := strings.TrimSpace(string(output))
+	if len(root) < 1 {
+		return "", fmt.Errorf("repositories root is empty")
+	}
+
+	return filepath.Join(root, repository), nil
+}
+
+func (s *Svn) DeleteRepository(repository string) error {
+	if len(repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+
+	_, err := s.runCmd("Remove-SvnRepository", repository, "-Confirm:$false")
+	return err
+}
+
+func (s *Svn) RenameRepository(repository, newName string) error {
+	if len(repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+	if len(newName) < 1 {
+		return fmt.Errorf("new name is empty")
+	}
+
+	_, err := s.runCmd("Rename-SvnRepository", repository, "-NewName", newName)
+	return err
+}
+
 func (s *Svn) GetPermissions(repository, path string) ([]*model.SvnPermission, error) {
 	if len(repository) < 1 {
 		return nil, fmt.Errorf("repository is empty")
@@ -243,6 +292,45 @@ func (s *Svn) RemovePermission(repository, path, accountId string) error {
 	return nil
 }
 
+// GetAccessRules returns the rules of the repository and of all repositories (*),
+// all rules are returned when repository is empty.
+func (s *Svn) GetAccessRules(repository string) ([]*model.SvnAccessRule, error) {
+	output, err := s.runCmd("Get-SvnAccessRule", "|", "Select", "Repository,Path,Access,AccountId,AccountName",
+		"|", "ConvertTo-Csv", "-NoTypeInformation")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.SvnAccessRule, 0)
+	rows := s.getCsvRows(output)
+	for i := 0; i < len(rows); i++ {
+		row := rows[i]
+		item := &model.SvnAccessRule{
+			Repository:  row["Repository"],
+			Path:        row["Path"],
+			AccountId:   row["AccountId"],
+			AccountName: row["AccountName"],
+			AccessLevel: model.SvnPermissionNoAccess,
+		}
+		if len(item.Repository) < 1 || len(item.AccountId) < 1 {
+			continue
+		}
+		if len(repository) > 0 && item.Repository != "*" && strings.ToLower(item.Repository) != strings.ToLower(repository) {
+			continue
+		}
+
+		level := strings.ToLower(row["Access"])
+		if level == "readonly" {
+			item.AccessLevel = model.SvnPermissionReadOnly
+		} else if level == "readwrite" {
+			item.AccessLevel = model.SvnPermissionReadWrite
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
 func (s *Svn) getRepository(line string) *model.SvnRepositoryItem {
 	// Name Revisions URL
 	// ---- --------- ---
@@ -0,0 +1,294 @@
+package assist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	svnArchiveMessage = "repository is archived and read-only"
+)
+
+var svnArchiveHooks = []string{"pre-commit", "pre-lock", "pre-revprop-change"}
+
+// SvnAdmin runs svnadmin and svnlook against a local repository folder,
+// it works for both VisualSVN Server and authz backends.
+type SvnAdmin struct {
+	base
+}
+
+func (s *SvnAdmin) GetActivity(repository, folder string) (*model.SvnRepositoryActivity, error) {
+	output, err := s.runCmd("svnlook", "youngest", folder)
+	if err != nil {
+		return nil, err
+	}
+	activity := &model.SvnRepositoryActivity{
+		Repository: repository,
+		Archived:   s.IsArchived(folder),
+	}
+	activity.Revisions, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+
+	if activity.Revisions > 0 {
+		output, err = s.runCmd("svnlook", "date", folder)
+		if err != nil {
+			return nil, err
+		}
+		commitTime, ok := s.getDate(output)
+		if ok {
+			activity.LastCommitTime = commitTime.Format("2006-01-02 15:04:05")
+		}
+	}
+
+	output, err = s.runCmd("svnadmin", "lslocks", folder)
+	if err != nil {
+		return nil, err
+	}
+	activity.Locks = s.getLocks(output)
+
+	return activity, nil
+}
+
+// CheckActivity refuses repositories with active locks, and repositories
+// committed within recentDays unless force is set.
+func (s *SvnAdmin) CheckActivity(activity *model.SvnRepositoryActivity, recentDays int, force bool, now time.Time) error {
+	if activity == nil {
+		return fmt.Errorf("activity is nil")
+	}
+	if len(activity.Locks) > 0 {
+		return fmt.Errorf("repository '%s' has %d active lock(s), e.g. %s",
+			activity.Repository, len(activity.Locks), activity.Locks[0].Path)
+	}
+	if force || recentDays < 1 {
+		return nil
+	}
+	commitTime, err := time.ParseInLocation("2006-01-02 15:04:05", activity.LastCommitTime, time.Local)
+	if err != nil {
+		return nil
+	}
+	if now.Sub(commitTime) < time.Duration(recentDays)*24*time.Hour {
+		return fmt.Errorf("repository '%s' was committed recently (%s), within %d days",
+			activity.Repository, activity.LastCommitTime, recentDays)
+	}
+
+	return nil
+}
+
+func (s *SvnAdmin) Dump(folder, filePath string) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0777)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	errors := &bytes.Buffer{}
+	cmd := exec.Command("svnadmin", "dump", "--quiet", folder)
+	cmd.Stdout = file
+	cmd.Stderr = errors
+	err = cmd.Run()
+	if err != nil {
+		file.Close()
+		os.Remove(filePath)
+		output, _ := s.decode(errors.Bytes())
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// Archive installs hook scripts rejecting commits, locks and revision property changes,
+// existing hooks are kept with a .bak suffix.
+func (s *SvnAdmin) Archive(folder string) error {
+	hooks := filepath.Join(folder, "hooks")
+	for i := 0; i < len(svnArchiveHooks); i++ {
+		hookPath := filepath.Join(hooks, s.hookName(svnArchiveHooks[i]))
+		paths := s.hookPaths(folder, svnArchiveHooks[i])
+		for j := 0; j < len(paths); j++ {
+			if paths[j] == hookPath {
+				continue
+			}
+			_, err := os.Stat(paths[j])
+			if err != nil {
+				continue
+			}
+			err = os.Rename(paths[j], paths[j]+".bak")
+			if err != nil {
+				return err
+			}
+		}
+		err := s.writeHook(hookPath, []byte(s.archiveHook()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SvnAdmin) IsArchived(folder string) bool {
+	hookPath, enabled := s.findHook(folder, svnArchiveHooks[0])
+	if !enabled {
+		return false
+	}
+	data, err := ioutil.ReadFile(hookPath)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(data), svnArchiveMessage)
+}
+
+func (s *SvnAdmin) archiveHook() string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("@echo off\r\necho %s 1>&2\r\nexit 1\r\n", svnArchiveMessage)
+	}
+
+	return fmt.Sprintf("#!/bin/sh\necho \"%s\" >&2\nexit 1\n", svnArchiveMessage)
+}
+
+func (s *SvnAdmin) writeHook(hookPath string, data []byte) error {
+	_, err := os.Stat(hookPath)
+	if err == nil {
+		err = os.Rename(hookPath, hookPath+".bak")
+		if err != nil {
+			return err
+		}
+	}
+
+	return ioutil.WriteFile(hookPath, data, 0755)
+}
+
+func (s *SvnAdmin) hookName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".bat"
+	}
+
+	return name
+}
+
+// hookExtensions lists the extensions of hook files in the order svn looks for them
+func (s *SvnAdmin) hookExtensions() []string {
+	if runtime.GOOS == "windows" {
+		return []string{".exe", ".cmd", ".bat"}
+	}
+
+	return []string{""}
+}
+
+// hookPaths lists the file paths the hook may be installed as.
+func (s *SvnAdmin) hookPaths(folder, hook string) []string {
+	extensions := s.hookExtensions()
+	paths := make([]string, 0, len(extensions))
+	for i := 0; i < len(extensions); i++ {
+		paths = append(paths, filepath.Join(folder, "hooks", hook+extensions[i]))
+	}
+
+	return paths
+}
+
+// findHook returns the path of the installed hook without the disabled suffix, empty if not installed.
+func (s *SvnAdmin) findHook(folder, hook string) (string, bool) {
+	paths := s.hookPaths(folder, hook)
+	for i := 0; i < len(paths); i++ {
+		_, err := os.Stat(paths[i])
+		if err == nil {
+			return paths[i], true
+		}
+	}
+	for i := 0; i < len(paths); i++ {
+		_, err := os.Stat(paths[i] + svnHookDisabledSuffix)
+		if err == nil {
+			return paths[i], false
+		}
+	}
+
+	return "", false
+}
+
+func (s *SvnAdmin) getDate(text []byte) (time.Time, bool) {
+	// 2020-10-19 08:30:00 +0800 (Mon, 19 Oct 2020)
+	value := strings.TrimSpace(string(text))
+	index := strings.Index(value, " (")
+	if index > 0 {
+		value = value[:index]
+	}
+	t, err := time.Parse("2006-01-02 15:04:05 -0700", value)
+	if err != nil {
+		return t, false
+	}
+
+	return t.Local(), true
+}
+
+func (s *SvnAdmin) getLocks(text []byte) []*model.SvnLock {
+	// Path: /trunk/design.docx
+	// UUID Token: opaquelocktoken:6f3c1a2e-0f0e-4b8e-a6f1-2f4f4c1f9a10
+	// Owner: alice
+	// Created: 2020-10-19 08:30:00 +0800 (Mon, 19 Oct 2020)
+	// Expires:
+	// Comment (1 line):
+	locks := make([]*model.SvnLock, 0)
+	var lock *model.SvnLock
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "Path:") {
+			lock = &model.SvnLock{
+				Path: strings.TrimSpace(strings.TrimPrefix(line, "Path:")),
+			}
+			locks = append(locks, lock)
+		} else if lock == nil {
+			continue
+		} else if strings.HasPrefix(line, "Owner:") {
+			lock.Owner = strings.TrimSpace(strings.TrimPrefix(line, "Owner:"))
+		} else if strings.HasPrefix(line, "Created:") {
+			created, ok := s.getDate([]byte(strings.TrimPrefix(line, "Created:")))
+			if ok {
+				lock.Created = created.Format("2006-01-02 15:04:05")
+			}
+		}
+	}
+
+	return locks
+}
+
+func (s *SvnAdmin) decode(v []byte) ([]byte, error) {
+	if runtime.GOOS == "windows" {
+		return s.toUtf8(v)
+	}
+
+	return v, nil
+}
+
+func (s *SvnAdmin) runCmd(name string, arg ...string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	cmd := exec.Command(name, arg...)
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		output, _ := s.decode(buf.Bytes())
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+
+	return s.decode(buf.Bytes())
+}
@@ -0,0 +1,90 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSvnAdmin_getLocks(t *testing.T) {
+	output := `Path: /trunk/design.docx
+UUID Token: opaquelocktoken:6f3c1a2e-0f0e-4b8e-a6f1-2f4f4c1f9a10
+Owner: alice
+Created: 2020-10-19 08:30:00 +0000 (Mon, 19 Oct 2020)
+Expires: 
+Comment (1 line):
+editing
+
+Path: /trunk/logo.png
+UUID Token: opaquelocktoken:7a1b2c3d-0f0e-4b8e-a6f1-2f4f4c1f9a11
+Owner: bob
+Created: 2020-10-19 09:00:00 +0000 (Mon, 19 Oct 2020)
+Expires: 
+Comment (0 lines):
+
+`
+	admin := &SvnAdmin{}
+	locks := admin.getLocks([]byte(output))
+	if len(locks) != 2 || locks[0].Path != "/trunk/design.docx" || locks[1].Owner != "bob" || len(locks[0].Created) < 1 {
+		t.Error("locks:", fmtItem(locks))
+	}
+}
+
+func TestSvnAdmin_CheckActivity(t *testing.T) {
+	admin := &SvnAdmin{}
+	now := time.Date(2020, 10, 19, 8, 30, 0, 0, time.Local)
+	activity := &model.SvnRepositoryActivity{
+		Repository:     "test",
+		LastCommitTime: "2020-10-10 08:30:00",
+		Locks:          []*model.SvnLock{},
+	}
+	if admin.CheckActivity(activity, 30, false, now) == nil {
+		t.Error("recent commit expected error")
+	}
+	if err := admin.CheckActivity(activity, 30, true, now); err != nil {
+		t.Error("force:", err)
+	}
+	if err := admin.CheckActivity(activity, 5, false, now); err != nil {
+		t.Error("old commit:", err)
+	}
+	activity.Locks = append(activity.Locks, &model.SvnLock{Path: "/trunk/a.txt"})
+	if admin.CheckActivity(activity, 0, true, now) == nil {
+		t.Error("lock expected error")
+	}
+}
+
+func TestSvnAdmin_Archive(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	err = os.MkdirAll(filepath.Join(folder, "hooks"), 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := &SvnAdmin{}
+	hookPath := filepath.Join(folder, "hooks", admin.hookName("pre-commit"))
+	err = ioutil.WriteFile(hookPath, []byte("exit 0\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.IsArchived(folder) {
+		t.Error("archived before archive")
+	}
+	err = admin.Archive(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !admin.IsArchived(folder) {
+		t.Error("not archived after archive")
+	}
+	_, err = os.Stat(hookPath + ".bak")
+	if err != nil {
+		t.Error("existing hook not kept:", err)
+	}
+}
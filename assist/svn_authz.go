@@ -0,0 +1,540 @@
+package assist
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var svnAuthzMutex = &sync.Mutex{}
+
+// SvnAuthz manages repositories created by svnadmin under Root,
+// access rules are kept in the authz file shared by Apache or svnserve.
+type SvnAuthz struct {
+	base
+
+	Root      string
+	AuthzFile string
+	Url       string
+}
+
+func (s *SvnAuthz) GetRepositories(folder bool) ([]*model.SvnRepositoryItem, error) {
+	if len(s.Root) < 1 {
+		return nil, fmt.Errorf("repository root is empty")
+	}
+	infos, err := ioutil.ReadDir(s.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.SvnRepositoryItem, 0)
+	for i := 0; i < len(infos); i++ {
+		info := infos[i]
+		if !info.IsDir() || !s.isRepository(info.Name()) {
+			continue
+		}
+
+		item := &model.SvnRepositoryItem{
+			Repository: info.Name(),
+			Name:       info.Name(),
+			Path:       "/",
+			Type:       model.SvnRepositoryItemKindRepository,
+			Url:        s.itemUrl(info.Name(), "/"),
+			Children:   make([]*model.SvnRepositoryItem, 0),
+		}
+		item.Id = s.uniqueId(item.Repository, item.Path)
+		output, oe := s.runCmd("svnlook", "youngest", s.repositoryPath(item.Repository))
+		if oe != nil {
+			return nil, oe
+		}
+		item.Revisions, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+
+		if folder {
+			err = s.getRepositoryFolders(item, folder)
+			if err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *SvnAuthz) NewRepository(repository string, folders []string) error {
+	folder, err := s.GetRepositoryPath(repository)
+	if err != nil {
+		return err
+	}
+	admin := &SvnAdmin{}
+	paths, err := admin.Folders(folders)
+	if err != nil {
+		return err
+	}
+	_, err = s.runCmd("svnadmin", "create", folder)
+	if err != nil {
+		return err
+	}
+	if len(paths) < 1 {
+		return nil
+	}
+
+	url := admin.fileUrl(folder)
+	args := []string{"mkdir", "--parents", "-m", "Initial structure"}
+	for i := 0; i < len(paths); i++ {
+		args = append(args, url+paths[i])
+	}
+	_, err = s.runCmd("svn", args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SvnAuthz) GetRepositoryFolders(repository, path string, recursive bool) ([]*model.SvnRepositoryItem, error) {
+	parent := &model.SvnRepositoryItem{
+		Repository: repository,
+		Path:       path,
+	}
+	err := s.getRepositoryFolders(parent, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	return parent.Children, nil
+}
+
+func (s *SvnAuthz) GetRepositoryPath(repository string) (string, error) {
+	if len(repository) < 1 {
+		return "", fmt.Errorf("repository is empty")
+	}
+	if strings.ContainsAny(repository, "/\\:") || repository == "." || repository == ".." {
+		return "", fmt.Errorf("repository '%s' invalid", repository)
+	}
+	if len(s.Root) < 1 {
+		return "", fmt.Errorf("repository root is empty")
+	}
+
+	return s.repositoryPath(repository), nil
+}
+
+func (s *SvnAuthz) DeleteRepository(repository string) error {
+	folder, err := s.GetRepositoryPath(repository)
+	if err != nil {
+		return err
+	}
+	if !s.isRepository(repository) {
+		return fmt.Errorf("repository '%s' not exist", repository)
+	}
+	err = os.RemoveAll(folder)
+	if err != nil {
+		return err
+	}
+
+	return s.modify(func(authz *svnAuthzFile) error {
+		sections := make([]*svnAuthzSection, 0)
+		for i := 0; i < len(authz.Sections); i++ {
+			name, _, ok := authz.sectionPath(authz.Sections[i].Name)
+			if ok && name == repository {
+				continue
+			}
+			sections = append(sections, authz.Sections[i])
+		}
+		authz.Sections = sections
+		return nil
+	})
+}
+
+func (s *SvnAuthz) RenameRepository(repository, newName string) error {
+	folder, err := s.GetRepositoryPath(repository)
+	if err != nil {
+		return err
+	}
+	if !s.isRepository(repository) {
+		return fmt.Errorf("repository '%s' not exist", repository)
+	}
+	newFolder, err := s.GetRepositoryPath(newName)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(newFolder)
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("repository '%s' already exists", newName)
+	}
+	err = os.Rename(folder, newFolder)
+	if err != nil {
+		return err
+	}
+
+	return s.modify(func(authz *svnAuthzFile) error {
+		for i := 0; i < len(authz.Sections); i++ {
+			section := authz.Sections[i]
+			name, path, ok := authz.sectionPath(section.Name)
+			if ok && name == repository {
+				section.Name = authz.sectionName(newName, path)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SvnAuthz) GetPermissions(repository, path string) ([]*model.SvnPermission, error) {
+	if len(repository) < 1 {
+		return nil, fmt.Errorf("repository is empty")
+	}
+	if len(path) < 1 {
+		return nil, fmt.Errorf("path is empty")
+	}
+	rules, err := s.GetAccessRules(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolvePermissions(rules, repository, path), nil
+}
+
+func (s *SvnAuthz) GetUserPermissions(accountId string) ([]*model.SvnPermissionUser, error) {
+	if len(accountId) < 1 {
+		return nil, fmt.Errorf("account id is empty")
+	}
+	authz, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.SvnPermissionUser, 0)
+	for i := 0; i < len(authz.Sections); i++ {
+		section := authz.Sections[i]
+		repository, path, ok := authz.sectionPath(section.Name)
+		if !ok {
+			continue
+		}
+		rule := section.get(accountId)
+		if rule == nil {
+			continue
+		}
+		items = append(items, &model.SvnPermissionUser{
+			Repository:  repository,
+			Path:        path,
+			AccessLevel: authz.accessLevel(rule.Value),
+		})
+	}
+
+	return items, nil
+}
+
+func (s *SvnAuthz) AddPermission(repository, path, accountId string, accessLevel int) error {
+	if len(repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+	if len(path) < 1 {
+		return fmt.Errorf("path is empty")
+	}
+	if len(accountId) < 1 {
+		return fmt.Errorf("accountId is empty")
+	}
+
+	return s.modify(func(authz *svnAuthzFile) error {
+		section := authz.addSection(authz.sectionName(repository, path))
+		if section.get(accountId) != nil {
+			return fmt.Errorf("access rule for '%s' in [%s] already exists", accountId, section.Name)
+		}
+		section.set(accountId, authz.accessValue(accessLevel))
+		return nil
+	})
+}
+
+func (s *SvnAuthz) SetPermission(repository, path, accountId string, accessLevel int) error {
+	if len(repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+	if len(path) < 1 {
+		return fmt.Errorf("path is empty")
+	}
+	if len(accountId) < 1 {
+		return fmt.Errorf("accountId is empty")
+	}
+
+	return s.modify(func(authz *svnAuthzFile) error {
+		section := authz.addSection(authz.sectionName(repository, path))
+		section.set(accountId, authz.accessValue(accessLevel))
+		return nil
+	})
+}
+
+func (s *SvnAuthz) RemovePermission(repository, path, accountId string) error {
+	if len(repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+	if len(path) < 1 {
+		return fmt.Errorf("path is empty")
+	}
+	if len(accountId) < 1 {
+		return fmt.Errorf("accountId is empty")
+	}
+
+	return s.modify(func(authz *svnAuthzFile) error {
+		name := authz.sectionName(repository, path)
+		section := authz.section(name)
+		if section == nil || !section.remove(accountId) {
+			return fmt.Errorf("access rule for '%s' in [%s] not exist", accountId, name)
+		}
+		return nil
+	})
+}
+
+// GetAccessRules returns the rules of the repository and of all repositories (*),
+// all rules are returned when repository is empty.
+func (s *SvnAuthz) GetAccessRules(repository string) ([]*model.SvnAccessRule, error) {
+	authz, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.SvnAccessRule, 0)
+	for i := 0; i < len(authz.Sections); i++ {
+		section := authz.Sections[i]
+		name, path, ok := authz.sectionPath(section.Name)
+		if !ok {
+			continue
+		}
+		if len(repository) > 0 && name != "*" && name != repository {
+			continue
+		}
+		rules := section.rules()
+		for j := 0; j < len(rules); j++ {
+			rule := rules[j]
+			items = append(items, &model.SvnAccessRule{
+				Repository:  name,
+				Path:        path,
+				AccountId:   rule.Key,
+				AccountName: rule.Key,
+				AccessLevel: authz.accessLevel(rule.Value),
+				Group:       authz.isGroup(rule.Key),
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// GetAccountGroups returns the groups matching the account: @groups including nested ones,
+// &aliases of the account, $authenticated and *; $anonymous and * for the anonymous account ($anonymous).
+func (s *SvnAuthz) GetAccountGroups(accountId string) ([]string, error) {
+	if len(accountId) < 1 {
+		return nil, fmt.Errorf("account id is empty")
+	}
+	if accountId == svnAuthzAnonymous {
+		return []string{svnAuthzAnonymous, "*"}, nil
+	}
+	authz, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]bool{accountId: true}
+	aliases := authz.section(svnAuthzSectionAliases)
+	if aliases != nil {
+		rules := aliases.rules()
+		for i := 0; i < len(rules); i++ {
+			if strings.TrimSpace(rules[i].Value) == accountId {
+				members["&"+rules[i].Key] = true
+			}
+		}
+	}
+
+	groups := authz.groups()
+	results := make([]string, 0)
+	for found := true; found; {
+		found = false
+		for name, items := range groups {
+			group := "@" + name
+			if members[group] {
+				continue
+			}
+			for i := 0; i < len(items); i++ {
+				if members[items[i]] {
+					members[group] = true
+					results = append(results, group)
+					found = true
+					break
+				}
+			}
+		}
+	}
+	for name := range members {
+		if strings.HasPrefix(name, "&") {
+			results = append(results, name)
+		}
+	}
+	sort.Strings(results)
+
+	return append(results, "$authenticated", "*"), nil
+}
+
+func (s *SvnAuthz) getRepositoryFolders(parent *model.SvnRepositoryItem, recursive bool) error {
+	if parent == nil {
+		return fmt.Errorf("parent is nil")
+	}
+	if len(parent.Repository) < 1 {
+		return fmt.Errorf("repository is empty")
+	}
+	if len(parent.Path) < 1 {
+		return fmt.Errorf("path is empty")
+	}
+	if parent.Children == nil {
+		parent.Children = make([]*model.SvnRepositoryItem, 0)
+	}
+
+	output, err := s.runCmd("svnlook", "tree", "--full-paths", "--non-recursive",
+		s.repositoryPath(parent.Repository), parent.Path)
+	if err != nil {
+		return err
+	}
+	parent.Children = append(parent.Children, s.getTreeFolders(parent.Repository, parent.Path, output)...)
+
+	if recursive {
+		c := len(parent.Children)
+		for i := 0; i < c; i++ {
+			err = s.getRepositoryFolders(parent.Children[i], recursive)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *SvnAuthz) getTreeFolders(repository, parent string, text []byte) []*model.SvnRepositoryItem {
+	// svnlook tree --full-paths --non-recursive /var/svn/test /
+	// /
+	// branches/
+	// tags/
+	// trunk/
+	items := make([]*model.SvnRepositoryItem, 0)
+	parent = "/" + strings.Trim(parent, "/")
+	lines := strings.Split(string(text), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasSuffix(line, "/") {
+			continue
+		}
+		folder := "/" + strings.Trim(line, "/")
+		if folder == parent || s.parentPath(folder) != parent {
+			continue
+		}
+
+		item := &model.SvnRepositoryItem{
+			Repository: repository,
+			Name:       folder[strings.LastIndex(folder, "/")+1:],
+			Path:       folder,
+			Type:       model.SvnRepositoryItemKindFolder,
+			Url:        s.itemUrl(repository, folder),
+			Children:   make([]*model.SvnRepositoryItem, 0),
+		}
+		item.Id = s.uniqueId(item.Repository, item.Path)
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+func (s *SvnAuthz) load() (*svnAuthzFile, error) {
+	if len(s.AuthzFile) < 1 {
+		return nil, fmt.Errorf("authz file is empty")
+	}
+	authz := &svnAuthzFile{}
+	data, err := ioutil.ReadFile(s.AuthzFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return authz, authz.parse(nil)
+		}
+		return nil, err
+	}
+	err = authz.parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", s.AuthzFile, err)
+	}
+
+	return authz, nil
+}
+
+func (s *SvnAuthz) modify(change func(authz *svnAuthzFile) error) error {
+	svnAuthzMutex.Lock()
+	defer svnAuthzMutex.Unlock()
+
+	authz, err := s.load()
+	if err != nil {
+		return err
+	}
+	err = change(authz)
+	if err != nil {
+		return err
+	}
+
+	tempPath := s.AuthzFile + ".tmp"
+	err = ioutil.WriteFile(tempPath, authz.bytes(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, s.AuthzFile)
+}
+
+func (s *SvnAuthz) isRepository(name string) bool {
+	folder := s.repositoryPath(name)
+	_, err := os.Stat(filepath.Join(folder, "format"))
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(folder, "db"))
+	if err != nil {
+		return false
+	}
+
+	return info.IsDir()
+}
+
+func (s *SvnAuthz) repositoryPath(repository string) string {
+	return filepath.Join(s.Root, repository)
+}
+
+func (s *SvnAuthz) itemUrl(repository, path string) string {
+	if len(s.Url) < 1 {
+		return ""
+	}
+
+	return strings.TrimSuffix(s.Url, "/") + "/" + repository + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (s *SvnAuthz) runCmd(name string, arg ...string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	cmd := exec.Command(name, arg...)
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(buf.String()))
+	}
+
+	return buf.Bytes(), nil
+}
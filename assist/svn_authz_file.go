@@ -0,0 +1,259 @@
+package assist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"strings"
+)
+
+const (
+	svnAuthzSectionGroups  = "groups"
+	svnAuthzSectionAliases = "aliases"
+	svnAuthzAnonymous      = "$anonymous"
+)
+
+type svnAuthzLine struct {
+	Key   string
+	Value string
+	Text  string // comment or blank line, kept as is
+}
+
+type svnAuthzSection struct {
+	Name  string
+	Lines []*svnAuthzLine
+}
+
+func (s *svnAuthzSection) get(key string) *svnAuthzLine {
+	for i := 0; i < len(s.Lines); i++ {
+		line := s.Lines[i]
+		if len(line.Key) > 0 && line.Key == key {
+			return line
+		}
+	}
+
+	return nil
+}
+
+func (s *svnAuthzSection) set(key, value string) {
+	line := s.get(key)
+	if line != nil {
+		line.Value = value
+		return
+	}
+
+	line = &svnAuthzLine{Key: key, Value: value}
+	index := len(s.Lines)
+	for index > 0 && len(s.Lines[index-1].Key) < 1 && len(strings.TrimSpace(s.Lines[index-1].Text)) < 1 {
+		index--
+	}
+	s.Lines = append(s.Lines, nil)
+	copy(s.Lines[index+1:], s.Lines[index:])
+	s.Lines[index] = line
+}
+
+func (s *svnAuthzSection) remove(key string) bool {
+	for i := 0; i < len(s.Lines); i++ {
+		if len(s.Lines[i].Key) > 0 && s.Lines[i].Key == key {
+			s.Lines = append(s.Lines[:i], s.Lines[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *svnAuthzSection) rules() []*svnAuthzLine {
+	results := make([]*svnAuthzLine, 0)
+	for i := 0; i < len(s.Lines); i++ {
+		if len(s.Lines[i].Key) > 0 {
+			results = append(results, s.Lines[i])
+		}
+	}
+
+	return results
+}
+
+// svnAuthzFile is a path-based authorization file used by mod_authz_svn and svnserve,
+// see http://svnbook.red-bean.com/en/1.7/svn.serverconfig.pathbasedauthz.html
+type svnAuthzFile struct {
+	base
+
+	Sections []*svnAuthzSection
+}
+
+func (s *svnAuthzFile) parse(text []byte) error {
+	s.Sections = make([]*svnAuthzSection, 0)
+	section := &svnAuthzSection{Lines: make([]*svnAuthzLine, 0)}
+	s.Sections = append(s.Sections, section)
+
+	number := 0
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		number++
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		line := strings.TrimSpace(raw)
+		if len(line) < 1 || line[0] == '#' || line[0] == ';' {
+			section.Lines = append(section.Lines, &svnAuthzLine{Text: raw})
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return fmt.Errorf("line %d: invalid section '%s'", number, line)
+			}
+			section = &svnAuthzSection{
+				Name:  strings.TrimSpace(line[1 : len(line)-1]),
+				Lines: make([]*svnAuthzLine, 0),
+			}
+			s.Sections = append(s.Sections, section)
+			continue
+		}
+
+		index := strings.Index(line, "=")
+		if index < 1 {
+			return fmt.Errorf("line %d: invalid rule '%s'", number, line)
+		}
+		if len(section.Name) < 1 {
+			return fmt.Errorf("line %d: rule '%s' outside of section", number, line)
+		}
+		section.Lines = append(section.Lines, &svnAuthzLine{
+			Key:   strings.TrimSpace(line[:index]),
+			Value: strings.TrimSpace(line[index+1:]),
+		})
+	}
+
+	return scanner.Err()
+}
+
+func (s *svnAuthzFile) bytes() []byte {
+	buf := &bytes.Buffer{}
+	for i := 0; i < len(s.Sections); i++ {
+		section := s.Sections[i]
+		if len(section.Name) > 0 {
+			buf.WriteString(fmt.Sprintf("[%s]\n", section.Name))
+		}
+		for j := 0; j < len(section.Lines); j++ {
+			line := section.Lines[j]
+			if len(line.Key) > 0 {
+				if len(line.Value) > 0 {
+					buf.WriteString(fmt.Sprintf("%s = %s\n", line.Key, line.Value))
+				} else {
+					buf.WriteString(fmt.Sprintf("%s =\n", line.Key))
+				}
+			} else {
+				buf.WriteString(line.Text)
+				buf.WriteString("\n")
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (s *svnAuthzFile) section(name string) *svnAuthzSection {
+	for i := 0; i < len(s.Sections); i++ {
+		if len(s.Sections[i].Name) > 0 && s.Sections[i].Name == name {
+			return s.Sections[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *svnAuthzFile) addSection(name string) *svnAuthzSection {
+	section := s.section(name)
+	if section != nil {
+		return section
+	}
+
+	c := len(s.Sections)
+	if c > 0 {
+		last := s.Sections[c-1]
+		lc := len(last.Lines)
+		if (len(last.Name) > 0 || lc > 0) && (lc < 1 || len(last.Lines[lc-1].Key) > 0 || len(strings.TrimSpace(last.Lines[lc-1].Text)) > 0) {
+			last.Lines = append(last.Lines, &svnAuthzLine{})
+		}
+	}
+	section = &svnAuthzSection{
+		Name:  name,
+		Lines: make([]*svnAuthzLine, 0),
+	}
+	s.Sections = append(s.Sections, section)
+
+	return section
+}
+
+// groups returns the members of each group, members may be users, @groups or &aliases
+func (s *svnAuthzFile) groups() map[string][]string {
+	results := make(map[string][]string)
+	section := s.section(svnAuthzSectionGroups)
+	if section == nil {
+		return results
+	}
+
+	rules := section.rules()
+	for i := 0; i < len(rules); i++ {
+		members := make([]string, 0)
+		items := strings.Split(rules[i].Value, ",")
+		for j := 0; j < len(items); j++ {
+			member := strings.TrimSpace(items[j])
+			if len(member) > 0 {
+				members = append(members, member)
+			}
+		}
+		results[rules[i].Key] = members
+	}
+
+	return results
+}
+
+func (s *svnAuthzFile) sectionName(repository, path string) string {
+	path = s.normalizePath(path)
+	if len(repository) < 1 || repository == "*" {
+		return path
+	}
+
+	return fmt.Sprintf("%s:%s", repository, path)
+}
+
+// sectionPath splits a section name into repository ("*" for any) and path,
+// ok is false for [groups], [aliases] and malformed names.
+func (s *svnAuthzFile) sectionPath(name string) (repository string, path string, ok bool) {
+	if strings.HasPrefix(name, "/") {
+		return "*", s.normalizePath(name), true
+	}
+	index := strings.Index(name, ":/")
+	if index < 1 {
+		return "", "", false
+	}
+
+	return name[:index], s.normalizePath(name[index+1:]), true
+}
+
+// isGroup reports whether the rule key is a group, i.e. @group, $authenticated, $anonymous or *
+func (s *svnAuthzFile) isGroup(key string) bool {
+	key = strings.TrimPrefix(key, "~")
+	return strings.HasPrefix(key, "@") || strings.HasPrefix(key, "$") || key == "*"
+}
+
+func (s *svnAuthzFile) accessLevel(value string) int {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if strings.Contains(value, "w") {
+		return model.SvnPermissionReadWrite
+	} else if strings.Contains(value, "r") {
+		return model.SvnPermissionReadOnly
+	}
+
+	return model.SvnPermissionNoAccess
+}
+
+func (s *svnAuthzFile) accessValue(level int) string {
+	if level == model.SvnPermissionReadWrite {
+		return "rw"
+	} else if level == model.SvnPermissionReadOnly {
+		return "r"
+	}
+
+	return ""
+}
@@ -0,0 +1,137 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSvnAuthz = `# access rules
+[groups]
+devs = alice, bob
+leads = carol, @devs
+
+[/]
+* = r
+$authenticated = r
+
+[test:/]
+@devs = rw
+
+[test:/trunk]
+alice =
+`
+
+func TestSvnAuthzFile_parse(t *testing.T) {
+	authz := &svnAuthzFile{}
+	err := authz.parse([]byte(testSvnAuthz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(authz.bytes()) != testSvnAuthz {
+		t.Error("bytes:\n", string(authz.bytes()))
+	}
+
+	groups := authz.groups()
+	if len(groups["devs"]) != 2 || groups["leads"][1] != "@devs" {
+		t.Error("groups:", fmtItem(groups))
+	}
+
+	repository, path, ok := authz.sectionPath("test:/trunk")
+	if !ok || repository != "test" || path != "/trunk" {
+		t.Error("section:", repository, path, ok)
+	}
+	repository, path, ok = authz.sectionPath("/")
+	if !ok || repository != "*" || path != "/" {
+		t.Error("section:", repository, path, ok)
+	}
+	_, _, ok = authz.sectionPath(svnAuthzSectionGroups)
+	if ok {
+		t.Error("section: groups is not a path")
+	}
+
+	err = authz.parse([]byte("* = r\n"))
+	if err == nil {
+		t.Error("parse: rule outside of section expected error")
+	}
+}
+
+func TestSvnAuthz_Permission(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-authz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	filePath := filepath.Join(folder, "authz")
+	err = ioutil.WriteFile(filePath, []byte(testSvnAuthz), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svn := &SvnAuthz{AuthzFile: filePath}
+	items, err := svn.GetPermissions("test", "/trunk/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := make(map[string]*model.SvnPermission)
+	for i := 0; i < len(items); i++ {
+		levels[items[i].AccountId] = items[i]
+	}
+	if len(items) != 4 || levels["alice"].AccessLevel != model.SvnPermissionNoAccess || !levels["alice"].Inherited ||
+		levels["@devs"].AccessLevel != model.SvnPermissionReadWrite || levels["*"].AccessLevel != model.SvnPermissionReadOnly ||
+		levels["alice"].Group || !levels["@devs"].Group || !levels["*"].Group {
+		t.Error("permissions:", fmtItem(items))
+	}
+
+	err = svn.AddPermission("test", "/trunk", "alice", model.SvnPermissionReadOnly)
+	if err == nil {
+		t.Error("add: duplicate rule expected error")
+	}
+	err = svn.AddPermission("test", "/tags/", "bob", model.SvnPermissionReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svn.SetPermission("*", "/", "$authenticated", model.SvnPermissionReadWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svn.RemovePermission("test", "/trunk", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svn.RemovePermission("test", "/trunk", "alice")
+	if err == nil {
+		t.Error("remove: missing rule expected error")
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "$authenticated = rw\n") || !strings.Contains(text, "\n[test:/tags]\nbob = r\n") ||
+		strings.Contains(text, "alice =\n") || !strings.HasPrefix(text, "# access rules\n") {
+		t.Error("file:\n", text)
+	}
+
+	users, err := svn.GetUserPermissions("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Repository != "test" || users[0].Path != "/tags" {
+		t.Error("user permissions:", fmtItem(users))
+	}
+}
+
+func TestSvnAuthz_getTreeFolders(t *testing.T) {
+	svn := &SvnAuthz{Url: "https://svn.example.com/svn/"}
+	output := "trunk/\ntrunk/src/\ntrunk/readme.txt\ntrunk/doc/\n"
+	items := svn.getTreeFolders("test", "/trunk", []byte(output))
+	if len(items) != 2 || items[0].Name != "doc" || items[1].Path != "/trunk/src" ||
+		items[1].Url != "https://svn.example.com/svn/test/trunk/src" {
+		t.Error("folders:", fmtItem(items))
+	}
+}
@@ -0,0 +1,20 @@
+package assist
+
+import "github.com/csby/gwin/model"
+
+// SvnBackend is implemented by Svn (VisualSVN Server) and SvnAuthz (svnadmin repositories with an authz file)
+type SvnBackend interface {
+	GetRepositories(folder bool) ([]*model.SvnRepositoryItem, error)
+	NewRepository(repository string, folders []string) error
+	GetRepositoryFolders(repository, path string, recursive bool) ([]*model.SvnRepositoryItem, error)
+	GetRepositoryPath(repository string) (string, error)
+	DeleteRepository(repository string) error
+	RenameRepository(repository, newName string) error
+
+	GetPermissions(repository, path string) ([]*model.SvnPermission, error)
+	GetUserPermissions(accountId string) ([]*model.SvnPermissionUser, error)
+	AddPermission(repository, path, accountId string, accessLevel int) error
+	SetPermission(repository, path, accountId string, accessLevel int) error
+	RemovePermission(repository, path, accountId string) error
+	GetAccessRules(repository string) ([]*model.SvnAccessRule, error)
+}
@@ -0,0 +1,508 @@
+package assist
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	svnBackupTimeFormat = "2006-01-02 15:04:05"
+)
+
+// SvnBackup keeps gzip compressed svnadmin dump or hotcopy archives in Folder,
+// each with a json file holding its revision range and sha256 checksum.
+type SvnBackup struct {
+	SvnAdmin
+
+	Folder string
+}
+
+func (s *SvnBackup) GetBackups(repository string) ([]*model.SvnBackup, error) {
+	items := make([]*model.SvnBackup, 0)
+	infos, err := ioutil.ReadDir(s.Folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return items, nil
+		}
+		return nil, err
+	}
+
+	for i := 0; i < len(infos); i++ {
+		info := infos[i]
+		if info.IsDir() || filepath.Ext(info.Name()) != ".json" {
+			continue
+		}
+		data, re := ioutil.ReadFile(filepath.Join(s.Folder, info.Name()))
+		if re != nil {
+			return nil, re
+		}
+		item := &model.SvnBackup{}
+		err = json.Unmarshal(data, item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", info.Name(), err)
+		}
+		if len(repository) > 0 && item.Repository != repository {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Repository != items[j].Repository {
+			return items[i].Repository < items[j].Repository
+		}
+		return items[i].Time < items[j].Time
+	})
+
+	return items, nil
+}
+
+func (s *SvnBackup) GetBackup(id string) (*model.SvnBackup, error) {
+	items, err := s.GetBackups("")
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(items); i++ {
+		if items[i].Id == id {
+			return items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("backup '%s' not exist", id)
+}
+
+func (s *SvnBackup) Backup(repository, folder, kind string, now time.Time, progress func(revision, total int)) (*model.SvnBackup, error) {
+	if len(repository) < 1 {
+		return nil, fmt.Errorf("repository is empty")
+	}
+	if len(s.Folder) < 1 {
+		return nil, fmt.Errorf("backup folder is empty")
+	}
+	output, err := s.runCmd("svnlook", "youngest", folder)
+	if err != nil {
+		return nil, err
+	}
+	youngest, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid youngest revision '%s'", strings.TrimSpace(string(output)))
+	}
+
+	backup := &model.SvnBackup{
+		Repository:   repository,
+		Kind:         kind,
+		FromRevision: 0,
+		ToRevision:   youngest,
+		Time:         now.Format(svnBackupTimeFormat),
+	}
+	if kind == model.SvnBackupKindIncremental {
+		previous, pe := s.getLatestDump(repository)
+		if pe != nil {
+			return nil, pe
+		}
+		if previous == nil {
+			return nil, fmt.Errorf("no previous backup of repository '%s', a full backup is required", repository)
+		}
+		if previous.ToRevision >= youngest {
+			return nil, fmt.Errorf("no new revisions since r%d", previous.ToRevision)
+		}
+		backup.FromRevision = previous.ToRevision + 1
+	} else if kind != model.SvnBackupKindFull && kind != model.SvnBackupKindHotcopy {
+		return nil, fmt.Errorf("kind '%s' not supported", kind)
+	}
+
+	backup.Id = fmt.Sprintf("%s-%s-%s", repository, kind, now.Format("20060102150405"))
+	if kind == model.SvnBackupKindHotcopy {
+		backup.File = backup.Id + ".tar.gz"
+	} else {
+		backup.File = backup.Id + ".dump.gz"
+	}
+	err = os.MkdirAll(s.Folder, 0777)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := filepath.Join(s.Folder, backup.File)
+	tempPath := filePath + ".tmp"
+	err = s.write(tempPath, backup, func(w io.Writer) error {
+		total := backup.ToRevision - backup.FromRevision + 1
+		if kind == model.SvnBackupKindHotcopy {
+			return s.hotcopy(folder, filepath.Join(s.Folder, backup.Id+".hotcopy"), w, total, progress)
+		}
+
+		cmd := exec.Command("svnadmin", "dump", folder,
+			"-r", fmt.Sprintf("%d:%d", backup.FromRevision, backup.ToRevision))
+		if kind == model.SvnBackupKindIncremental {
+			cmd.Args = append(cmd.Args, "--incremental")
+		}
+		cmd.Stdout = w
+		return s.run(cmd, false, backup.FromRevision, total, progress)
+	})
+	if err != nil {
+		os.Remove(tempPath)
+		return nil, err
+	}
+	err = os.Rename(tempPath, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(backup, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filepath.Join(s.Folder, backup.Id+".json"), data, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// Restore creates a new repository at folder from the backup, an incremental backup
+// is loaded after the full and incremental backups it depends on.
+func (s *SvnBackup) Restore(id, folder string, progress func(revision, total int)) error {
+	_, err := os.Stat(folder)
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("folder '%s' already exists", folder)
+	}
+	backups, err := s.GetBackups("")
+	if err != nil {
+		return err
+	}
+	chain, err := s.getChain(backups, id)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(chain); i++ {
+		err = s.Verify(chain[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.restore(chain, folder, progress)
+	if err != nil {
+		os.RemoveAll(folder)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SvnBackup) Verify(backup *model.SvnBackup) error {
+	file, err := os.Open(filepath.Join(s.Folder, backup.File))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != backup.Sha256 {
+		return fmt.Errorf("checksum of backup '%s' mismatch", backup.Id)
+	}
+
+	return nil
+}
+
+func (s *SvnBackup) restore(chain []*model.SvnBackup, folder string, progress func(revision, total int)) error {
+	first := chain[0]
+	last := chain[len(chain)-1]
+	total := last.ToRevision - first.FromRevision + 1
+	if first.Kind == model.SvnBackupKindHotcopy {
+		err := s.read(first, func(r io.Reader) error {
+			return s.extract(r, folder)
+		})
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(total, total)
+		}
+		return nil
+	}
+
+	_, err := s.runCmd("svnadmin", "create", folder)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(chain); i++ {
+		err = s.read(chain[i], func(r io.Reader) error {
+			cmd := exec.Command("svnadmin", "load", folder)
+			cmd.Stdin = r
+			return s.run(cmd, true, first.FromRevision, total, progress)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SvnBackup) getChain(backups []*model.SvnBackup, id string) ([]*model.SvnBackup, error) {
+	var current *model.SvnBackup
+	for i := 0; i < len(backups); i++ {
+		if backups[i].Id == id {
+			current = backups[i]
+			break
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("backup '%s' not exist", id)
+	}
+
+	chain := []*model.SvnBackup{current}
+	for current.Kind == model.SvnBackupKindIncremental {
+		var previous *model.SvnBackup
+		for i := 0; i < len(backups); i++ {
+			item := backups[i]
+			if item.Repository != current.Repository || item.Kind == model.SvnBackupKindHotcopy {
+				continue
+			}
+			if item.ToRevision == current.FromRevision-1 && item.Time < current.Time {
+				previous = item
+			}
+		}
+		if previous == nil {
+			return nil, fmt.Errorf("backup before r%d of '%s' not exist", current.FromRevision, current.Repository)
+		}
+		chain = append([]*model.SvnBackup{previous}, chain...)
+		current = previous
+	}
+
+	return chain, nil
+}
+
+func (s *SvnBackup) getLatestDump(repository string) (*model.SvnBackup, error) {
+	backups, err := s.GetBackups(repository)
+	if err != nil {
+		return nil, err
+	}
+	var latest *model.SvnBackup
+	for i := 0; i < len(backups); i++ {
+		if backups[i].Kind == model.SvnBackupKindHotcopy {
+			continue
+		}
+		if latest == nil || backups[i].ToRevision > latest.ToRevision {
+			latest = backups[i]
+		}
+	}
+
+	return latest, nil
+}
+
+func (s *SvnBackup) write(filePath string, backup *model.SvnBackup, content func(w io.Writer) error) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	writer := gzip.NewWriter(io.MultiWriter(file, hash))
+	err = content(writer)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	backup.Size = info.Size()
+	backup.Sha256 = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+func (s *SvnBackup) read(backup *model.SvnBackup, content func(r io.Reader) error) error {
+	file, err := os.Open(filepath.Join(s.Folder, backup.File))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return content(reader)
+}
+
+func (s *SvnBackup) hotcopy(folder, tempFolder string, w io.Writer, total int, progress func(revision, total int)) error {
+	os.RemoveAll(tempFolder)
+	defer os.RemoveAll(tempFolder)
+
+	cmd := exec.Command("svnadmin", "hotcopy", folder, tempFolder)
+	err := s.run(cmd, true, 0, total, progress)
+	if err != nil {
+		return err
+	}
+
+	writer := tar.NewWriter(w)
+	err = filepath.Walk(tempFolder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(tempFolder, path)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		err = writer.WriteHeader(header)
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(writer, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func (s *SvnBackup) extract(r io.Reader, folder string) error {
+	root, err := filepath.Abs(folder)
+	if err != nil {
+		return err
+	}
+	reader := tar.NewReader(r)
+	for {
+		header, re := reader.Next()
+		if re == io.EOF {
+			break
+		}
+		if re != nil {
+			return re
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(header.Name))
+		if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return fmt.Errorf("invalid entry '%s'", header.Name)
+		}
+		if header.Typeflag == tar.TypeDir {
+			err = os.MkdirAll(path, 0777)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0777)
+		if err != nil {
+			return err
+		}
+		file, fe := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+		if fe != nil {
+			return fe
+		}
+		_, err = io.Copy(file, reader)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run reports progress from the "revision N" lines svnadmin prints to stdout
+// (load, hotcopy) or stderr (dump).
+func (s *SvnBackup) run(cmd *exec.Cmd, stdout bool, offset, total int, progress func(revision, total int)) error {
+	errors := &bytes.Buffer{}
+	var pipe io.ReadCloser
+	var err error
+	if stdout {
+		pipe, err = cmd.StdoutPipe()
+		cmd.Stderr = errors
+	} else {
+		pipe, err = cmd.StderrPipe()
+	}
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(pipe)
+	for scanner.Scan() {
+		line := scanner.Text()
+		revision, ok := s.getRevision(line)
+		if ok {
+			if progress != nil {
+				progress(revision-offset+1, total)
+			}
+		} else if !stdout {
+			errors.WriteString(line)
+			errors.WriteString("\n")
+		}
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		output, _ := s.decode(errors.Bytes())
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func (s *SvnBackup) getRevision(line string) (int, bool) {
+	// * Dumped revision 12.
+	// * Copied revision 12.
+	// ------- Committed revision 12 >>>
+	index := strings.Index(line, "revision ")
+	if index < 0 || !strings.Contains(line, "*") && !strings.Contains(line, "---") {
+		return 0, false
+	}
+	value := line[index+len("revision "):]
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	revision, err := strconv.Atoi(value[:end])
+	if err != nil {
+		return 0, false
+	}
+
+	return revision, true
+}
@@ -0,0 +1,190 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSvnBackup_getRevision(t *testing.T) {
+	backup := &SvnBackup{}
+	lines := map[string]int{
+		"* Dumped revision 12.":              12,
+		"* Copied revision 3.":               3,
+		"------- Committed revision 128 >>>": 128,
+	}
+	for line, expect := range lines {
+		revision, ok := backup.getRevision(line)
+		if !ok || revision != expect {
+			t.Error(line, revision, ok)
+		}
+	}
+	_, ok := backup.getRevision("<<< Started new transaction, based on original revision 5")
+	if ok {
+		t.Error("transaction line is not progress")
+	}
+}
+
+func TestSvnBackup_getChain(t *testing.T) {
+	backup := &SvnBackup{}
+	backups := []*model.SvnBackup{
+		{Id: "a-full-1", Repository: "a", Kind: model.SvnBackupKindFull, FromRevision: 0, ToRevision: 10, Time: "2020-10-01 08:00:00"},
+		{Id: "a-hot-1", Repository: "a", Kind: model.SvnBackupKindHotcopy, FromRevision: 0, ToRevision: 15, Time: "2020-10-02 08:00:00"},
+		{Id: "a-inc-1", Repository: "a", Kind: model.SvnBackupKindIncremental, FromRevision: 11, ToRevision: 20, Time: "2020-10-03 08:00:00"},
+		{Id: "a-inc-2", Repository: "a", Kind: model.SvnBackupKindIncremental, FromRevision: 21, ToRevision: 25, Time: "2020-10-04 08:00:00"},
+		{Id: "b-inc-1", Repository: "b", Kind: model.SvnBackupKindIncremental, FromRevision: 11, ToRevision: 12, Time: "2020-10-04 08:00:00"},
+	}
+
+	chain, err := backup.getChain(backups, "a-inc-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || chain[0].Id != "a-full-1" || chain[2].Id != "a-inc-2" {
+		t.Error("chain:", fmtItem(chain))
+	}
+	_, err = backup.getChain(backups, "b-inc-1")
+	if err == nil {
+		t.Error("broken chain expected error")
+	}
+}
+
+func TestSvnBackup_Verify(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	backup := &SvnBackup{Folder: folder}
+	item := &model.SvnBackup{Id: "a-full-1", File: "a-full-1.dump.gz"}
+	err = backup.write(folder+"/"+item.File, item, func(w io.Writer) error {
+		_, we := io.WriteString(w, "SVN-fs-dump-format-version: 2\n")
+		return we
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Size < 1 || len(item.Sha256) != 64 {
+		t.Error("backup:", fmtItem(item))
+	}
+	err = backup.Verify(item)
+	if err != nil {
+		t.Error("verify:", err)
+	}
+	err = backup.read(item, func(r io.Reader) error {
+		data, re := ioutil.ReadAll(r)
+		if string(data) != "SVN-fs-dump-format-version: 2\n" {
+			t.Error("content:", string(data))
+		}
+		return re
+	})
+	if err != nil {
+		t.Error("read:", err)
+	}
+
+	if item.Sha256[0] == '0' {
+		item.Sha256 = "1" + item.Sha256[1:]
+	} else {
+		item.Sha256 = "0" + item.Sha256[1:]
+	}
+	if backup.Verify(item) == nil {
+		t.Error("verify: checksum mismatch expected error")
+	}
+}
+
+func TestSvnBackup_BackupRestore(t *testing.T) {
+	for _, name := range []string{"svnadmin", "svnlook"} {
+		_, err := exec.LookPath(name)
+		if err != nil {
+			t.Skip(name, "not found")
+		}
+	}
+	folder, err := ioutil.TempDir("", "gwin-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	repository := filepath.Join(folder, "repo")
+	admin := &SvnAdmin{}
+	_, err = admin.runCmd("svnadmin", "create", repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svnTestLoad(repository, 1, "trunk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backup := &SvnBackup{Folder: filepath.Join(folder, "backup")}
+	now := time.Date(2020, 10, 19, 8, 30, 0, 0, time.Local)
+	full, err := backup.Backup("repo", repository, model.SvnBackupKindFull, now, nil)
+	if err != nil {
+		t.Fatal("full:", err)
+	}
+	if full.FromRevision != 0 || full.ToRevision != 1 {
+		t.Error("full:", fmtItem(full))
+	}
+	err = svnTestLoad(repository, 2, "branches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	incremental, err := backup.Backup("repo", repository, model.SvnBackupKindIncremental, now.Add(time.Second), nil)
+	if err != nil {
+		t.Fatal("incremental:", err)
+	}
+	if incremental.FromRevision != 2 || incremental.ToRevision != 2 {
+		t.Error("incremental:", fmtItem(incremental))
+	}
+	hotcopy, err := backup.Backup("repo", repository, model.SvnBackupKindHotcopy, now.Add(2*time.Second), nil)
+	if err != nil {
+		t.Fatal("hotcopy:", err)
+	}
+
+	ids := []string{incremental.Id, hotcopy.Id}
+	for i := 0; i < len(ids); i++ {
+		target := filepath.Join(folder, fmt.Sprintf("restored%d", i))
+		err = backup.Restore(ids[i], target, nil)
+		if err != nil {
+			t.Error(ids[i], "restore:", err)
+			continue
+		}
+		output, le := admin.runCmd("svnlook", "youngest", target)
+		if le != nil || strings.TrimSpace(string(output)) != "2" {
+			t.Error(ids[i], "youngest:", string(output), le)
+		}
+		output, le = admin.runCmd("svnlook", "tree", target)
+		if le != nil || !strings.Contains(string(output), "trunk/") || !strings.Contains(string(output), "branches/") {
+			t.Error(ids[i], "tree:", string(output), le)
+		}
+		if backup.Restore(ids[i], target, nil) == nil {
+			t.Error(ids[i], "restore to an existing folder: error expected")
+		}
+	}
+}
+
+// svnTestLoad commits a folder to the repository by loading a minimal dump.
+func svnTestLoad(repository string, revision int, path string) error {
+	message := "add " + path
+	props := fmt.Sprintf("K 7\nsvn:log\nV %d\n%s\nPROPS-END\n", len(message), message)
+	dump := fmt.Sprintf("SVN-fs-dump-format-version: 2\n\n"+
+		"Revision-number: %d\nProp-content-length: %d\nContent-length: %d\n\n%s\n"+
+		"Node-path: %s\nNode-kind: dir\nNode-action: add\nProp-content-length: 10\nContent-length: 10\n\nPROPS-END\n\n",
+		revision, len(props), len(props), props, path)
+
+	cmd := exec.Command("svnadmin", "load", "--quiet", repository)
+	cmd.Stdin = strings.NewReader(dump)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
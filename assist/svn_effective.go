@@ -0,0 +1,140 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"strings"
+)
+
+// SvnEffective calculates the effective access of an account, the nearest path with matching rules decides
+type SvnEffective struct {
+	base
+
+	AccountFirst bool
+}
+
+func (s *SvnEffective) Calculate(repository, path, accountId string, groups []string, rules []*model.SvnAccessRule) *model.SvnEffectivePermission {
+	path = s.normalizePath(path)
+	result := &model.SvnEffectivePermission{
+		Repository:  repository,
+		Path:        path,
+		AccountId:   accountId,
+		AccessLevel: model.SvnPermissionNoAccess,
+		Groups:      groups,
+		Matches:     make([]*model.SvnAccessRule, 0),
+	}
+	if result.Groups == nil {
+		result.Groups = make([]string, 0)
+	}
+
+	members := make(map[string]bool)
+	members[strings.ToLower(accountId)] = true
+	for i := 0; i < len(groups); i++ {
+		members[strings.ToLower(groups[i])] = true
+	}
+
+	var decided []*model.SvnAccessRule
+	for current := path; ; current = s.parentPath(current) {
+		for _, name := range []string{repository, "*"} {
+			level := make([]*model.SvnAccessRule, 0)
+			for i := 0; i < len(rules); i++ {
+				rule := rules[i]
+				if !strings.EqualFold(rule.Repository, name) || s.normalizePath(rule.Path) != current {
+					continue
+				}
+				if !s.matches(rule.AccountId, members) {
+					continue
+				}
+				level = append(level, rule)
+			}
+			result.Matches = append(result.Matches, level...)
+			if decided == nil && len(level) > 0 {
+				decided = level
+			}
+			if name == "*" {
+				break
+			}
+		}
+		if current == "/" {
+			break
+		}
+	}
+
+	if len(decided) < 1 {
+		result.Reason = fmt.Sprintf("no rule for '%s' or its groups from '%s' up to '/', access denied by default", accountId, path)
+		return result
+	}
+
+	result.Rule = s.decide(accountId, decided)
+	result.AccessLevel = result.Rule.AccessLevel
+	result.Reason = s.reason(result.Rule, decided, path)
+
+	return result
+}
+
+// matches reports whether the rule account is the account or one of its groups,
+// an inverted account (~user, ~@group) matches when it is neither.
+func (s *SvnEffective) matches(accountId string, members map[string]bool) bool {
+	if strings.HasPrefix(accountId, "~") {
+		return !members[strings.ToLower(accountId[1:])]
+	}
+
+	return members[strings.ToLower(accountId)]
+}
+
+func (s *SvnEffective) decide(accountId string, rules []*model.SvnAccessRule) *model.SvnAccessRule {
+	if s.AccountFirst {
+		for i := 0; i < len(rules); i++ {
+			if strings.EqualFold(rules[i].AccountId, accountId) {
+				return rules[i]
+			}
+		}
+	}
+
+	rule := rules[0]
+	for i := 1; i < len(rules); i++ {
+		if rules[i].AccessLevel > rule.AccessLevel {
+			rule = rules[i]
+		}
+	}
+
+	return rule
+}
+
+func (s *SvnEffective) reason(rule *model.SvnAccessRule, rules []*model.SvnAccessRule, path string) string {
+	sb := &strings.Builder{}
+	account := rule.AccountName
+	if len(account) < 1 {
+		account = rule.AccountId
+	}
+	if strings.HasPrefix(rule.AccountId, "~") {
+		fmt.Fprintf(sb, "inverted rule '%s'", account)
+	} else if rule.Group {
+		fmt.Fprintf(sb, "group '%s'", account)
+	} else {
+		fmt.Fprintf(sb, "account '%s'", account)
+	}
+	fmt.Fprintf(sb, " has %s on [%s:%s]", s.levelText(rule.AccessLevel), rule.Repository, rule.Path)
+	if s.normalizePath(rule.Path) != path {
+		sb.WriteString(", inherited as the nearest rule")
+	}
+	if len(rules) > 1 {
+		if s.AccountFirst && !rule.Group {
+			fmt.Fprintf(sb, ", the account rule overrides %d group rule(s) on the same path", len(rules)-1)
+		} else {
+			fmt.Fprintf(sb, ", the most permissive of %d matching rules on the same path", len(rules))
+		}
+	}
+
+	return sb.String()
+}
+
+func (s *SvnEffective) levelText(level int) string {
+	if level == model.SvnPermissionReadWrite {
+		return "read-write"
+	} else if level == model.SvnPermissionReadOnly {
+		return "read-only"
+	}
+
+	return "no access"
+}
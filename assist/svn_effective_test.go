@@ -0,0 +1,145 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSvnEffective_Calculate(t *testing.T) {
+	rules := []*model.SvnAccessRule{
+		{Repository: "*", Path: "/", AccountId: "S-1-5-11", AccessLevel: model.SvnPermissionReadOnly, Group: true},
+		{Repository: "test", Path: "/", AccountId: "devs", AccessLevel: model.SvnPermissionReadWrite, Group: true},
+		{Repository: "test", Path: "/trunk", AccountId: "alice", AccessLevel: model.SvnPermissionNoAccess},
+		{Repository: "test", Path: "/trunk", AccountId: "devs", AccessLevel: model.SvnPermissionReadWrite, Group: true},
+		{Repository: "test", Path: "/tags", AccountId: "qa", AccessLevel: model.SvnPermissionReadOnly, Group: true},
+		{Repository: "test", Path: "/tags", AccountId: "devs", AccessLevel: model.SvnPermissionReadWrite, Group: true},
+	}
+
+	// account rule overrides group rule on the same path
+	effective := &SvnEffective{AccountFirst: true}
+	result := effective.Calculate("test", "/trunk/src/", "alice", []string{"devs", "S-1-5-11"}, rules)
+	if result.AccessLevel != model.SvnPermissionNoAccess || result.Rule != rules[2] || len(result.Matches) != 4 {
+		t.Error("account first:", fmtItem(result))
+	}
+
+	// most permissive
+	effective.AccountFirst = false
+	result = effective.Calculate("test", "/trunk/src", "alice", []string{"devs", "S-1-5-11"}, rules)
+	if result.AccessLevel != model.SvnPermissionReadWrite || result.Rule != rules[3] {
+		t.Error("most permissive:", fmtItem(result))
+	}
+	result = effective.Calculate("test", "/tags/v1.0", "dave", []string{"qa", "devs"}, rules)
+	if result.AccessLevel != model.SvnPermissionReadWrite || result.Rule != rules[5] {
+		t.Error("groups:", fmtItem(result))
+	}
+
+	// inherited from all repositories
+	result = effective.Calculate("prod", "/trunk", "erin", []string{"S-1-5-11"}, rules)
+	if result.AccessLevel != model.SvnPermissionReadOnly || result.Rule != rules[0] || !strings.Contains(result.Reason, "inherited") {
+		t.Error("inherited:", fmtItem(result))
+	}
+
+	// no rule
+	result = effective.Calculate("prod", "/", "erin", nil, rules)
+	if result.AccessLevel != model.SvnPermissionNoAccess || result.Rule != nil || len(result.Groups) != 0 {
+		t.Error("no rule:", fmtItem(result))
+	}
+}
+
+func TestSvnAuthz_GetAccountGroups(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-authz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	filePath := filepath.Join(folder, "authz")
+	err = ioutil.WriteFile(filePath, []byte(testSvnAuthz), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svn := &SvnAuthz{AuthzFile: filePath}
+	groups, err := svn.GetAccountGroups("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(groups, ",") != "@devs,@leads,$authenticated,*" {
+		t.Error("groups:", groups)
+	}
+
+	rules, err := svn.GetAccessRules("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	effective := &SvnEffective{}
+	result := effective.Calculate("test", "/trunk", "alice", groups, rules)
+	if result.AccessLevel != model.SvnPermissionNoAccess || result.Rule.AccountId != "alice" {
+		t.Error("effective:", fmtItem(result))
+	}
+	result = effective.Calculate("test", "/trunk", "bob", []string{"@devs", "@leads", "$authenticated", "*"}, rules)
+	if result.AccessLevel != model.SvnPermissionReadWrite || result.Rule.AccountId != "@devs" {
+		t.Error("effective:", fmtItem(result))
+	}
+	result = effective.Calculate("test", "/trunk", "carol", []string{"@leads", "$authenticated", "*"}, rules)
+	if result.AccessLevel != model.SvnPermissionReadOnly || result.Rule.Repository != "*" {
+		t.Error("effective:", fmtItem(result))
+	}
+}
+
+func TestSvnEffective_Inverted(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-authz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	filePath := filepath.Join(folder, "authz")
+	err = ioutil.WriteFile(filePath, []byte(`[groups]
+admins = alice
+
+[test:/]
+* = r
+$anonymous = r
+
+[test:/secret]
+~@admins =
+@admins = rw
+
+[test:/private]
+~$anonymous = rw
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svn := &SvnAuthz{AuthzFile: filePath}
+	rules, err := svn.GetAccessRules("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	effective := &SvnEffective{}
+
+	groups, _ := svn.GetAccountGroups("bob")
+	result := effective.Calculate("test", "/secret/docs", "bob", groups, rules)
+	if result.AccessLevel != model.SvnPermissionNoAccess || result.Rule == nil || result.Rule.AccountId != "~@admins" {
+		t.Error("inverted group:", fmtItem(result))
+	}
+	groups, _ = svn.GetAccountGroups("alice")
+	result = effective.Calculate("test", "/secret", "alice", groups, rules)
+	if result.AccessLevel != model.SvnPermissionReadWrite || result.Rule.AccountId != "@admins" {
+		t.Error("not inverted:", fmtItem(result))
+	}
+	result = effective.Calculate("test", "/private", "alice", groups, rules)
+	if result.AccessLevel != model.SvnPermissionReadWrite || result.Rule.AccountId != "~$anonymous" {
+		t.Error("authenticated:", fmtItem(result))
+	}
+
+	groups, _ = svn.GetAccountGroups("$anonymous")
+	result = effective.Calculate("test", "/private", "$anonymous", groups, rules)
+	if result.AccessLevel != model.SvnPermissionReadOnly || result.Rule.Path != "/" {
+		t.Error("anonymous:", fmtItem(result))
+	}
+}
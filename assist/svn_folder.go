@@ -0,0 +1,177 @@
+package assist
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var svnCommittedRevision = regexp.MustCompile(`Committed revision (\d+)\.`)
+
+// NewFolder creates the folder and its missing parents in a single commit,
+// returns the committed revision.
+func (s *SvnAdmin) NewFolder(folder, itemPath, message, author string) (int, error) {
+	itemPath, err := s.ItemPath(itemPath)
+	if err != nil {
+		return 0, err
+	}
+	if itemPath == "/" {
+		return 0, fmt.Errorf("path is root")
+	}
+	if s.exists(folder, itemPath) {
+		return 0, fmt.Errorf("path '%s' already exists", itemPath)
+	}
+
+	output, err := s.runCmd("svn", s.commitArgs("mkdir", message, author,
+		"--parents", s.fileUrl(folder)+itemPath)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.getCommitted(output)
+}
+
+// Copy makes a server side copy of source at revision (0 for HEAD) to target,
+// returns the committed revision.
+func (s *SvnAdmin) Copy(folder, source string, revision int, target, message, author string) (int, error) {
+	source, err := s.ItemPath(source)
+	if err != nil {
+		return 0, err
+	}
+	target, err = s.ItemPath(target)
+	if err != nil {
+		return 0, err
+	}
+	if target == "/" || strings.HasPrefix(target+"/", source+"/") {
+		return 0, fmt.Errorf("target '%s' is inside source '%s'", target, source)
+	}
+	if s.exists(folder, target) {
+		return 0, fmt.Errorf("target '%s' already exists", target)
+	}
+
+	url := s.fileUrl(folder)
+	sourceUrl := url + source
+	if revision > 0 {
+		sourceUrl = fmt.Sprintf("%s@%d", sourceUrl, revision)
+	}
+	output, err := s.runCmd("svn", s.commitArgs("copy", message, author,
+		"--parents", sourceUrl, url+target)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.getCommitted(output)
+}
+
+// ItemPath cleans a repository path to the form /a/b, paths escaping the root are refused.
+func (s *SvnAdmin) ItemPath(itemPath string) (string, error) {
+	value := strings.TrimSpace(strings.ReplaceAll(itemPath, "\\", "/"))
+	if len(value) < 1 {
+		return "", fmt.Errorf("path is empty")
+	}
+	items := strings.Split(value, "/")
+	for i := 0; i < len(items); i++ {
+		if items[i] == ".." {
+			return "", fmt.Errorf("path '%s' invalid", itemPath)
+		}
+	}
+
+	return path.Clean("/" + value), nil
+}
+
+// Folders cleans the folder paths and adds their missing parents,
+// parents are listed before children.
+func (s *SvnAdmin) Folders(folders []string) ([]string, error) {
+	items := make(map[string]bool)
+	for i := 0; i < len(folders); i++ {
+		itemPath, err := s.ItemPath(folders[i])
+		if err != nil {
+			return nil, err
+		}
+		for itemPath != "/" {
+			items[itemPath] = true
+			itemPath = path.Dir(itemPath)
+		}
+	}
+
+	paths := make([]string, 0, len(items))
+	for itemPath := range items {
+		paths = append(paths, itemPath)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// Import commits the files and folders of the local source folder to itemPath,
+// returns the committed revision.
+func (s *SvnAdmin) Import(folder, source, itemPath, message, author string) (int, error) {
+	if len(itemPath) < 1 {
+		itemPath = "/"
+	}
+	itemPath, err := s.ItemPath(itemPath)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("source '%s' is not a folder", source)
+	}
+
+	url := s.fileUrl(folder)
+	if itemPath != "/" {
+		url += itemPath
+	}
+	output, err := s.runCmd("svn", s.commitArgs("import", message, author, source, url)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.getCommitted(output)
+}
+
+func (s *SvnAdmin) exists(folder, itemPath string) bool {
+	_, err := s.runCmd("svnlook", "tree", "--non-recursive", folder, itemPath)
+	return err == nil
+}
+
+func (s *SvnAdmin) commitArgs(command, message, author string, arg ...string) []string {
+	args := []string{command, "--non-interactive", "-m", message}
+	if len(author) > 0 {
+		args = append(args, "--username", author)
+	}
+
+	return append(args, arg...)
+}
+
+func (s *SvnAdmin) getCommitted(text []byte) (int, error) {
+	// Committing transaction...
+	// Committed revision 129.
+	match := svnCommittedRevision.FindSubmatch(text)
+	if len(match) < 2 {
+		return 0, fmt.Errorf("committed revision not found: %s", strings.TrimSpace(string(text)))
+	}
+
+	return strconv.Atoi(string(match[1]))
+}
+
+func (s *SvnAdmin) fileUrl(folder string) string {
+	abs, err := filepath.Abs(folder)
+	if err == nil {
+		folder = abs
+	}
+	folder = filepath.ToSlash(folder)
+	if !strings.HasPrefix(folder, "/") {
+		folder = "/" + folder
+	}
+
+	return "file://" + folder
+}
@@ -0,0 +1,70 @@
+package assist
+
+import (
+	"testing"
+)
+
+func TestSvnAdmin_ItemPath(t *testing.T) {
+	admin := &SvnAdmin{}
+	paths := map[string]string{
+		"trunk":              "/trunk",
+		"/trunk/docs/":       "/trunk/docs",
+		"\\branches\\dev":    "/branches/dev",
+		"/tags//v1.0/./":     "/tags/v1.0",
+		"/":                  "/",
+		" /trunk/a b ":       "/trunk/a b",
+		"/branches/release.": "/branches/release.",
+	}
+	for value, expect := range paths {
+		actual, err := admin.ItemPath(value)
+		if err != nil || actual != expect {
+			t.Errorf("'%s': expect '%s', actual '%s' (%v)", value, expect, actual, err)
+		}
+	}
+
+	invalids := []string{"", "  ", "/trunk/../..", "../trunk", "/tags/.."}
+	for _, value := range invalids {
+		_, err := admin.ItemPath(value)
+		if err == nil {
+			t.Errorf("'%s': error expected", value)
+		}
+	}
+}
+
+func TestSvnAdmin_getCommitted(t *testing.T) {
+	admin := &SvnAdmin{}
+	revision, err := admin.getCommitted([]byte("Committing transaction...\nCommitted revision 129.\n"))
+	if err != nil || revision != 129 {
+		t.Error(revision, err)
+	}
+	_, err = admin.getCommitted([]byte("svn: E160020: Path already exists"))
+	if err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestSvnAdmin_Folders(t *testing.T) {
+	admin := &SvnAdmin{}
+	paths, err := admin.Folders([]string{"/projA/trunk", "projA/branches/", "/projB/trunk", "/projA/trunk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"/projA", "/projA/branches", "/projA/trunk", "/projB", "/projB/trunk"}
+	if len(paths) != len(expect) {
+		t.Fatal("folders:", paths)
+	}
+	for i := 0; i < len(expect); i++ {
+		if paths[i] != expect[i] {
+			t.Errorf("folder %d: expect '%s', actual '%s'", i, expect[i], paths[i])
+		}
+	}
+
+	paths, err = admin.Folders(nil)
+	if err != nil || len(paths) != 0 {
+		t.Error("empty folders:", paths, err)
+	}
+	_, err = admin.Folders([]string{"/trunk", "/../trunk"})
+	if err == nil {
+		t.Error("error expected")
+	}
+}
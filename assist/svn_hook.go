@@ -0,0 +1,361 @@
+package assist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	svnHookDisabledSuffix = ".disabled"
+	svnHookMetaFile       = "gwin-hooks.json"
+)
+
+var svnHookNames = []string{
+	"start-commit", "pre-commit", "post-commit",
+	"pre-lock", "post-lock", "pre-unlock", "post-unlock",
+	"pre-revprop-change", "post-revprop-change",
+}
+
+// SvnHook installs hooks from a library of approved scripts, one sub folder per hook, e.g. pre-commit/commit-message.bat
+type SvnHook struct {
+	SvnAdmin
+
+	Library string
+}
+
+func (s *SvnHook) GetScripts() ([]*model.SvnHookScript, error) {
+	scripts := make([]*model.SvnHookScript, 0)
+	for i := 0; i < len(svnHookNames); i++ {
+		hook := svnHookNames[i]
+		files, err := ioutil.ReadDir(filepath.Join(s.Library, hook))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for j := 0; j < len(files); j++ {
+			file := files[j]
+			if file.IsDir() || !s.isScript(file.Name()) {
+				continue
+			}
+			data, err := ioutil.ReadFile(filepath.Join(s.Library, hook, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			scripts = append(scripts, &model.SvnHookScript{
+				Hook:   hook,
+				Name:   strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())),
+				File:   file.Name(),
+				Size:   file.Size(),
+				Sha256: s.checksum(data),
+			})
+		}
+	}
+
+	return scripts, nil
+}
+
+// GetHooks lists the installed hooks of the repository and compares them with the library scripts.
+func (s *SvnHook) GetHooks(repository, folder string, scripts []*model.SvnHookScript) ([]*model.SvnHook, error) {
+	meta, err := s.readMeta(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]*model.SvnHook, 0)
+	for i := 0; i < len(svnHookNames); i++ {
+		name := svnHookNames[i]
+		hookPath, enabled := s.findHook(folder, name)
+		if len(hookPath) < 1 {
+			continue
+		}
+		if !enabled {
+			hookPath += svnHookDisabledSuffix
+		}
+		data, err := ioutil.ReadFile(hookPath)
+		if err != nil {
+			return nil, err
+		}
+
+		hook := &model.SvnHook{
+			Repository: repository,
+			Hook:       name,
+			Script:     meta[name],
+			Enabled:    enabled,
+			Sha256:     s.checksum(data),
+		}
+		hook.Status = s.getStatus(hook, scripts)
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, nil
+}
+
+// Install copies the library script to the hook of the repository and enables it,
+// an existing hook not installed from the library is kept with a .bak suffix.
+func (s *SvnHook) Install(folder, hook, script string) error {
+	err := s.checkHook(folder, hook)
+	if err != nil {
+		return err
+	}
+	file, err := s.scriptFile(hook, script)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	meta, err := s.readMeta(folder)
+	if err != nil {
+		return err
+	}
+
+	managed := len(meta[hook]) > 0
+	hookPath := filepath.Join(folder, "hooks", s.hookFile(hook, file))
+	paths := s.hookPaths(folder, hook)
+	for i := 0; i < len(paths); i++ {
+		for _, filePath := range []string{paths[i], paths[i] + svnHookDisabledSuffix} {
+			if filePath == hookPath {
+				continue
+			}
+			_, err = os.Stat(filePath)
+			if err != nil {
+				continue
+			}
+			if managed {
+				err = os.Remove(filePath)
+			} else {
+				err = os.Rename(filePath, filePath+".bak")
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if managed {
+		err = ioutil.WriteFile(hookPath, data, 0755)
+	} else {
+		err = s.writeHook(hookPath, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	meta[hook] = script
+	return s.writeMeta(folder, meta)
+}
+
+func (s *SvnHook) Enable(folder, hook string) error {
+	err := s.checkHook(folder, hook)
+	if err != nil {
+		return err
+	}
+	hookPath, enabled := s.findHook(folder, hook)
+	if len(hookPath) < 1 {
+		return fmt.Errorf("hook '%s' not installed", hook)
+	}
+	if enabled {
+		return nil
+	}
+
+	return os.Rename(hookPath+svnHookDisabledSuffix, hookPath)
+}
+
+func (s *SvnHook) Disable(folder, hook string) error {
+	err := s.checkHook(folder, hook)
+	if err != nil {
+		return err
+	}
+	hookPath, enabled := s.findHook(folder, hook)
+	if len(hookPath) < 1 {
+		return fmt.Errorf("hook '%s' not installed", hook)
+	}
+	if !enabled {
+		return nil
+	}
+
+	return os.Rename(hookPath, hookPath+svnHookDisabledSuffix)
+}
+
+func (s *SvnHook) Remove(folder, hook string) error {
+	err := s.checkHook(folder, hook)
+	if err != nil {
+		return err
+	}
+	removed := false
+	paths := s.hookPaths(folder, hook)
+	for i := 0; i < len(paths); i++ {
+		for _, filePath := range []string{paths[i], paths[i] + svnHookDisabledSuffix} {
+			err = os.Remove(filePath)
+			if err == nil {
+				removed = true
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	if !removed {
+		return fmt.Errorf("hook '%s' not installed", hook)
+	}
+
+	meta, err := s.readMeta(folder)
+	if err != nil {
+		return err
+	}
+	if _, ok := meta[hook]; !ok {
+		return nil
+	}
+	delete(meta, hook)
+
+	return s.writeMeta(folder, meta)
+}
+
+func (s *SvnHook) getStatus(hook *model.SvnHook, scripts []*model.SvnHookScript) string {
+	if len(hook.Script) < 1 {
+		for i := 0; i < len(scripts); i++ {
+			script := scripts[i]
+			if script.Hook == hook.Hook && script.Sha256 == hook.Sha256 {
+				hook.Script = script.Name
+				return model.SvnHookStatusSame
+			}
+		}
+		return model.SvnHookStatusCustom
+	}
+
+	for i := 0; i < len(scripts); i++ {
+		script := scripts[i]
+		if script.Hook != hook.Hook || script.Name != hook.Script {
+			continue
+		}
+		if script.Sha256 == hook.Sha256 {
+			return model.SvnHookStatusSame
+		}
+		return model.SvnHookStatusDiffer
+	}
+
+	return model.SvnHookStatusMissing
+}
+
+func (s *SvnHook) checkHook(folder, hook string) error {
+	if !s.IsHookName(hook) {
+		return fmt.Errorf("hook '%s' invalid", hook)
+	}
+	for i := 0; i < len(svnArchiveHooks); i++ {
+		if svnArchiveHooks[i] == hook && s.IsArchived(folder) {
+			return fmt.Errorf("repository is archived, hook '%s' is reserved", hook)
+		}
+	}
+
+	return nil
+}
+
+func (s *SvnHook) IsHookName(hook string) bool {
+	for i := 0; i < len(svnHookNames); i++ {
+		if svnHookNames[i] == hook {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckScript returns an error if the script of the hook is not found in the library.
+func (s *SvnHook) CheckScript(hook, script string) error {
+	if !s.IsHookName(hook) {
+		return fmt.Errorf("hook '%s' invalid", hook)
+	}
+	_, err := s.scriptFile(hook, script)
+
+	return err
+}
+
+func (s *SvnHook) scriptFile(hook, script string) (string, error) {
+	if len(script) < 1 {
+		return "", fmt.Errorf("script is empty")
+	}
+	files, err := ioutil.ReadDir(filepath.Join(s.Library, hook))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for i := 0; i < len(files); i++ {
+		name := files[i].Name()
+		if files[i].IsDir() || !s.isScript(name) {
+			continue
+		}
+		if strings.TrimSuffix(name, filepath.Ext(name)) == script {
+			return filepath.Join(s.Library, hook, name), nil
+		}
+	}
+
+	return "", fmt.Errorf("script '%s' of hook '%s' not found in library", script, hook)
+}
+
+func (s *SvnHook) scriptExtensions() []string {
+	if runtime.GOOS == "windows" {
+		return s.hookExtensions()
+	}
+
+	return []string{"", ".sh", ".py", ".pl"}
+}
+
+func (s *SvnHook) isScript(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	extensions := s.scriptExtensions()
+	for i := 0; i < len(extensions); i++ {
+		if extensions[i] == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hookFile is the file name of the hook installed from the script file.
+func (s *SvnHook) hookFile(hook, file string) string {
+	if runtime.GOOS == "windows" {
+		return hook + strings.ToLower(filepath.Ext(file))
+	}
+
+	return hook
+}
+
+func (s *SvnHook) readMeta(folder string) (map[string]string, error) {
+	meta := make(map[string]string)
+	data, err := ioutil.ReadFile(filepath.Join(folder, "hooks", svnHookMetaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+func (s *SvnHook) writeMeta(folder string, meta map[string]string) error {
+	data, err := json.MarshalIndent(meta, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(folder, "hooks", svnHookMetaFile), data, 0644)
+}
+
+func (s *SvnHook) checksum(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,81 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSvnHook_Install(t *testing.T) {
+	root, err := ioutil.TempDir("", "gwin-hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	library := filepath.Join(root, "library")
+	folder := filepath.Join(root, "MyRepo")
+	os.MkdirAll(filepath.Join(library, "pre-commit"), 0777)
+	os.MkdirAll(filepath.Join(folder, "hooks"), 0777)
+	scriptPath := filepath.Join(library, "pre-commit", "commit-message.sh")
+	ioutil.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 0\n"), 0644)
+	ioutil.WriteFile(filepath.Join(library, "pre-commit", "check-size.ps1"), []byte("exit 0\n"), 0644)
+
+	hook := &SvnHook{Library: library}
+	scripts, err := hook.GetScripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 1 || scripts[0].Name != "commit-message" || scripts[0].Hook != "pre-commit" {
+		t.Fatal("scripts:", fmtItem(scripts))
+	}
+
+	err = hook.Install(folder, "pre-commit", "commit-message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := hook.GetHooks("MyRepo", folder, scripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 1 || hooks[0].Status != model.SvnHookStatusSame || !hooks[0].Enabled {
+		t.Fatal("hooks:", fmtItem(hooks))
+	}
+
+	ioutil.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 1\n"), 0644)
+	scripts, _ = hook.GetScripts()
+	hooks, _ = hook.GetHooks("MyRepo", folder, scripts)
+	if len(hooks) != 1 || hooks[0].Status != model.SvnHookStatusDiffer || hooks[0].Script != "commit-message" {
+		t.Error("differ:", fmtItem(hooks))
+	}
+
+	err = hook.Disable(folder, "pre-commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks, _ = hook.GetHooks("MyRepo", folder, scripts)
+	if len(hooks) != 1 || hooks[0].Enabled {
+		t.Error("disabled:", fmtItem(hooks))
+	}
+	err = hook.Enable(folder, "pre-commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hook.Remove(folder, "pre-commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks, _ = hook.GetHooks("MyRepo", folder, scripts)
+	if len(hooks) != 0 {
+		t.Error("removed:", fmtItem(hooks))
+	}
+	if hook.Install(folder, "pre-commit", "check-size") == nil {
+		t.Error("script not run by svn expected error")
+	}
+	if hook.Install(folder, "pre-build", "commit-message") == nil {
+		t.Error("invalid hook name expected error")
+	}
+}
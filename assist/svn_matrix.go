@@ -0,0 +1,145 @@
+package assist
+
+import (
+	"encoding/csv"
+	"github.com/csby/gwin/model"
+	"io"
+	"strings"
+)
+
+var svnMatrixHeader = []string{"Repository", "Path", "Account ID", "Account Name", "Access", "Inherited", "Group"}
+
+// SvnMatrix builds the repository × folder × account × access level report
+type SvnMatrix struct {
+	base
+
+	Account   string
+	WriteOnly bool
+
+	Rows []*model.SvnPermissionMatrixRow
+}
+
+// AddFolders appends the permissions of the repository root and its folders resolved from the access rules
+func (s *SvnMatrix) AddFolders(repository string, folders []*model.SvnRepositoryItem, rules []*model.SvnAccessRule) {
+	paths := s.appendPaths([]string{"/"}, folders)
+	for i := 0; i < len(paths); i++ {
+		s.Add(repository, paths[i], s.resolvePermissions(rules, repository, paths[i]))
+	}
+}
+
+// Add appends the permissions of the folder which pass the account and write access filters
+func (s *SvnMatrix) Add(repository, path string, permissions []*model.SvnPermission) {
+	if s.Rows == nil {
+		s.Rows = make([]*model.SvnPermissionMatrixRow, 0)
+	}
+	for i := 0; i < len(permissions); i++ {
+		permission := permissions[i]
+		if s.WriteOnly && permission.AccessLevel != model.SvnPermissionReadWrite {
+			continue
+		}
+		if len(s.Account) > 0 &&
+			!strings.EqualFold(s.Account, permission.AccountId) &&
+			!strings.EqualFold(s.Account, permission.AccountName) {
+			continue
+		}
+
+		s.Rows = append(s.Rows, &model.SvnPermissionMatrixRow{
+			Repository:  repository,
+			Path:        path,
+			AccountId:   permission.AccountId,
+			AccountName: permission.AccountName,
+			AccessLevel: permission.AccessLevel,
+			Inherited:   permission.Inherited,
+			Group:       permission.Group,
+		})
+	}
+}
+
+func (s *SvnMatrix) Table() [][]string {
+	table := make([][]string, 0, len(s.Rows)+1)
+	table = append(table, svnMatrixHeader)
+	for i := 0; i < len(s.Rows); i++ {
+		row := s.Rows[i]
+		table = append(table, []string{
+			row.Repository,
+			row.Path,
+			row.AccountId,
+			row.AccountName,
+			s.access(row.AccessLevel),
+			s.yesNo(row.Inherited),
+			s.yesNo(row.Group),
+		})
+	}
+
+	return table
+}
+
+// WriteCsv writes the report as UTF-8 CSV with BOM, so that Excel detects the encoding
+func (s *SvnMatrix) WriteCsv(w io.Writer) error {
+	_, err := w.Write([]byte{0xEF, 0xBB, 0xBF})
+	if err != nil {
+		return err
+	}
+	table := s.Table()
+	for i := 0; i < len(table); i++ {
+		for j := 0; j < len(table[i]); j++ {
+			table[i][j] = s.cell(table[i][j])
+		}
+	}
+	writer := csv.NewWriter(w)
+	err = writer.WriteAll(table)
+	if err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+func (s *SvnMatrix) WriteXlsx(w io.Writer) error {
+	xlsx := &Xlsx{
+		Sheet: "Permissions",
+		Rows:  s.Table(),
+	}
+
+	return xlsx.Write(w)
+}
+
+// cell keeps a value starting with =, +, -, @, tab or carriage return from being taken as a formula by spreadsheet applications
+func (s *SvnMatrix) cell(value string) string {
+	if len(value) > 0 && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+
+	return value
+}
+
+func (s *SvnMatrix) access(level int) string {
+	if level == model.SvnPermissionReadWrite {
+		return "ReadWrite"
+	} else if level == model.SvnPermissionReadOnly {
+		return "ReadOnly"
+	}
+
+	return "NoAccess"
+}
+
+func (s *SvnMatrix) yesNo(v bool) string {
+	if v {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+func (s *SvnMatrix) appendPaths(paths []string, folders []*model.SvnRepositoryItem) []string {
+	for i := 0; i < len(folders); i++ {
+		folder := folders[i]
+		if folder == nil {
+			continue
+		}
+		paths = append(paths, folder.Path)
+		paths = s.appendPaths(paths, folder.Children)
+	}
+
+	return paths
+}
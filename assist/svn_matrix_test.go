@@ -0,0 +1,136 @@
+package assist
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSvnMatrix_Add(t *testing.T) {
+	permissions := []*model.SvnPermission{
+		{AccountId: "S-1-5-32-545", AccountName: "BUILTIN\\Users", AccessLevel: model.SvnPermissionReadOnly, Inherited: true, Group: true},
+		{AccountId: "S-1-5-21-1104", AccountName: "EXAMPLE\\dev", AccessLevel: model.SvnPermissionReadWrite},
+	}
+
+	matrix := &SvnMatrix{WriteOnly: true}
+	matrix.Add("test", "/trunk", permissions)
+	if len(matrix.Rows) != 1 || matrix.Rows[0].AccountId != "S-1-5-21-1104" {
+		t.Error("write only:", fmtItem(matrix.Rows))
+	}
+
+	matrix = &SvnMatrix{Account: "builtin\\users"}
+	matrix.Add("test", "/", permissions)
+	matrix.Add("test", "/trunk", permissions)
+	if len(matrix.Rows) != 2 || !matrix.Rows[1].Group || matrix.Rows[1].Path != "/trunk" {
+		t.Error("account:", fmtItem(matrix.Rows))
+	}
+
+	buf := &bytes.Buffer{}
+	err := matrix.WriteCsv(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.TrimPrefix(buf.String(), "\xef\xbb\xbf")
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) != 3 || lines[0] != "Repository,Path,Account ID,Account Name,Access,Inherited,Group" ||
+		lines[2] != "test,/trunk,S-1-5-32-545,BUILTIN\\Users,ReadOnly,Yes,Yes" {
+		t.Error("csv:", text)
+	}
+}
+
+func TestSvnMatrix_AddFolders(t *testing.T) {
+	rules := []*model.SvnAccessRule{
+		{Repository: "*", Path: "/", AccountId: "*", AccessLevel: model.SvnPermissionReadOnly, Group: true},
+		{Repository: "test", Path: "/", AccountId: "@devs", AccessLevel: model.SvnPermissionReadWrite, Group: true},
+		{Repository: "test", Path: "/trunk/", AccountId: "@devs", AccessLevel: model.SvnPermissionReadOnly, Group: true},
+		{Repository: "test", Path: "/tags", AccountId: "=cmd", AccessLevel: model.SvnPermissionReadWrite},
+		{Repository: "prod", Path: "/trunk", AccountId: "bob", AccessLevel: model.SvnPermissionReadWrite},
+	}
+	folders := []*model.SvnRepositoryItem{
+		{Repository: "test", Path: "/branches"},
+		{Repository: "test", Path: "/tags"},
+		{Repository: "test", Path: "/trunk", Children: []*model.SvnRepositoryItem{
+			{Repository: "test", Path: "/trunk/src"},
+		}},
+	}
+
+	matrix := &SvnMatrix{}
+	matrix.AddFolders("test", folders, rules)
+	paths := make([]string, 0)
+	for i := 0; i < len(matrix.Rows); i++ {
+		row := matrix.Rows[i]
+		paths = append(paths, row.Path+":"+row.AccountId)
+	}
+	if strings.Join(paths, ",") != "/:@devs,/:*,/branches:@devs,/branches:*,/tags:=cmd,/tags:@devs,/tags:*,"+
+		"/trunk:@devs,/trunk:*,/trunk/src:@devs,/trunk/src:*" {
+		t.Fatal("paths:", paths)
+	}
+	if matrix.Rows[5].AccessLevel != model.SvnPermissionReadWrite || !matrix.Rows[5].Inherited ||
+		matrix.Rows[7].AccessLevel != model.SvnPermissionReadOnly || matrix.Rows[7].Inherited ||
+		matrix.Rows[9].AccessLevel != model.SvnPermissionReadOnly || !matrix.Rows[9].Inherited {
+		t.Error("inherited:", fmtItem(matrix.Rows))
+	}
+
+	table := matrix.Table()
+	if table[1][2] != "@devs" || table[5][2] != "=cmd" {
+		t.Error("table:", table)
+	}
+	buf := &bytes.Buffer{}
+	err := matrix.WriteCsv(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasPrefix(lines[1], "test,/,'@devs,") || !strings.HasPrefix(lines[5], "test,/tags,'=cmd,") ||
+		!strings.HasPrefix(lines[2], "test,/,*,") {
+		t.Error("escape:", lines)
+	}
+}
+
+func TestXlsx_Write(t *testing.T) {
+	xlsx := &Xlsx{
+		Sheet: "Permissions",
+		Rows: [][]string{
+			{"Repository", "Path"},
+			{"test", "/a&b<c>"},
+		},
+	}
+	buf := &bytes.Buffer{}
+	err := xlsx.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	for _, file := range reader.File {
+		rc, re := file.Open()
+		if re != nil {
+			t.Fatal(re)
+		}
+		data, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		parts[file.Name] = string(data)
+	}
+	if len(parts) != 6 || !strings.Contains(parts["xl/workbook.xml"], `name="Permissions"`) {
+		t.Error("parts:", len(parts))
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">/a&amp;b&lt;c&gt;</t></is></c>`) ||
+		!strings.Contains(sheet, `<c r="A1" t="inlineStr" s="1">`) {
+		t.Error("sheet:", sheet)
+	}
+
+	columns := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, expect := range columns {
+		if xlsx.column(index) != expect {
+			t.Errorf("column %d: expect %s, actual %s", index, expect, xlsx.column(index))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package assist
+
+import (
+	"fmt"
+	"github.com/csby/gwin/model"
+	"strings"
+)
+
+// SvnPermissionCopy plans and applies the changes making the target rules match the source rules,
+// rules are identified by repository, path and account.
+// In merge mode only missing rules are added, in replace mode differing rules are set
+// and target rules absent from the source are removed.
+type SvnPermissionCopy struct {
+	Replace bool
+}
+
+func (s *SvnPermissionCopy) Plan(source, target []*model.SvnAccessRule) []*model.SvnPermissionAction {
+	actions := make([]*model.SvnPermissionAction, 0)
+	targets := make(map[string]*model.SvnAccessRule)
+	for i := 0; i < len(target); i++ {
+		targets[s.key(target[i])] = target[i]
+	}
+
+	sources := make(map[string]bool)
+	for i := 0; i < len(source); i++ {
+		rule := source[i]
+		key := s.key(rule)
+		if sources[key] {
+			continue
+		}
+		sources[key] = true
+
+		action := &model.SvnPermissionAction{
+			Action:      model.SvnPermissionActionAdd,
+			Repository:  rule.Repository,
+			Path:        rule.Path,
+			AccountId:   rule.AccountId,
+			AccountName: rule.AccountName,
+			AccessLevel: rule.AccessLevel,
+		}
+		existing, ok := targets[key]
+		if ok {
+			action.OldAccessLevel = existing.AccessLevel
+			if existing.AccessLevel == rule.AccessLevel || !s.Replace {
+				action.Action = model.SvnPermissionActionKeep
+				action.AccessLevel = existing.AccessLevel
+			} else {
+				action.Action = model.SvnPermissionActionSet
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	if !s.Replace {
+		return actions
+	}
+	for i := 0; i < len(target); i++ {
+		rule := target[i]
+		if sources[s.key(rule)] {
+			continue
+		}
+		actions = append(actions, &model.SvnPermissionAction{
+			Action:         model.SvnPermissionActionRemove,
+			Repository:     rule.Repository,
+			Path:           rule.Path,
+			AccountId:      rule.AccountId,
+			AccountName:    rule.AccountName,
+			AccessLevel:    model.SvnPermissionNoAccess,
+			OldAccessLevel: rule.AccessLevel,
+		})
+	}
+
+	return actions
+}
+
+// Apply runs the planned actions, failures are recorded in the action and do not stop the others
+func (s *SvnPermissionCopy) Apply(svn SvnBackend, actions []*model.SvnPermissionAction) (applied int, failed int) {
+	for i := 0; i < len(actions); i++ {
+		action := actions[i]
+		var err error
+		switch action.Action {
+		case model.SvnPermissionActionAdd:
+			err = svn.AddPermission(action.Repository, action.Path, action.AccountId, action.AccessLevel)
+		case model.SvnPermissionActionSet:
+			err = svn.SetPermission(action.Repository, action.Path, action.AccountId, action.AccessLevel)
+		case model.SvnPermissionActionRemove:
+			err = svn.RemovePermission(action.Repository, action.Path, action.AccountId)
+		default:
+			continue
+		}
+		if err != nil {
+			action.Error = err.Error()
+			failed++
+		} else {
+			applied++
+		}
+	}
+
+	return
+}
+
+func (s *SvnPermissionCopy) key(rule *model.SvnAccessRule) string {
+	path := "/" + strings.Trim(rule.Path, "/")
+	return strings.ToLower(fmt.Sprintf("%s|%s|%s", rule.Repository, path, rule.AccountId))
+}
@@ -0,0 +1,102 @@
+package assist
+
+import (
+	"github.com/csby/gwin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSvnPermissionCopy_Plan(t *testing.T) {
+	source := []*model.SvnAccessRule{
+		{Repository: "test", Path: "/trunk", AccountId: "bob", AccessLevel: model.SvnPermissionReadWrite},
+		{Repository: "test", Path: "/tags", AccountId: "bob", AccessLevel: model.SvnPermissionReadOnly},
+		{Repository: "prod", Path: "/", AccountId: "bob", AccessLevel: model.SvnPermissionReadOnly},
+	}
+	target := []*model.SvnAccessRule{
+		{Repository: "test", Path: "/trunk/", AccountId: "bob", AccessLevel: model.SvnPermissionReadOnly},
+		{Repository: "test", Path: "/tags", AccountId: "bob", AccessLevel: model.SvnPermissionReadOnly},
+		{Repository: "dev", Path: "/", AccountId: "bob", AccessLevel: model.SvnPermissionReadWrite},
+	}
+
+	copier := &SvnPermissionCopy{}
+	actions := copier.Plan(source, target)
+	expects := []string{model.SvnPermissionActionKeep, model.SvnPermissionActionKeep, model.SvnPermissionActionAdd}
+	if len(actions) != len(expects) || actions[0].AccessLevel != model.SvnPermissionReadOnly {
+		t.Fatal("merge:", fmtItem(actions))
+	}
+	for i := 0; i < len(expects); i++ {
+		if actions[i].Action != expects[i] {
+			t.Errorf("merge %d: expect %s, actual %s", i, expects[i], actions[i].Action)
+		}
+	}
+
+	copier.Replace = true
+	actions = copier.Plan(source, target)
+	expects = []string{model.SvnPermissionActionSet, model.SvnPermissionActionKeep, model.SvnPermissionActionAdd, model.SvnPermissionActionRemove}
+	if len(actions) != len(expects) || actions[0].OldAccessLevel != model.SvnPermissionReadOnly || actions[3].Repository != "dev" {
+		t.Fatal("replace:", fmtItem(actions))
+	}
+	for i := 0; i < len(expects); i++ {
+		if actions[i].Action != expects[i] {
+			t.Errorf("replace %d: expect %s, actual %s", i, expects[i], actions[i].Action)
+		}
+	}
+}
+
+func TestSvnPermissionCopy_Apply(t *testing.T) {
+	folder, err := ioutil.TempDir("", "gwin-authz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	filePath := filepath.Join(folder, "authz")
+	err = ioutil.WriteFile(filePath, []byte(testSvnAuthz), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svn := &SvnAuthz{AuthzFile: filePath}
+	source, err := svn.GetPermissions("test", "/trunk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := make([]*model.SvnAccessRule, 0)
+	for i := 0; i < len(source); i++ {
+		if source[i].Inherited {
+			continue
+		}
+		rules = append(rules, &model.SvnAccessRule{
+			Repository:  "test",
+			Path:        "/branches/dev",
+			AccountId:   source[i].AccountId,
+			AccessLevel: source[i].AccessLevel,
+		})
+	}
+
+	copier := &SvnPermissionCopy{Replace: true}
+	actions := copier.Plan(rules, nil)
+	applied, failed := copier.Apply(svn, actions)
+	if applied != 1 || failed != 0 {
+		t.Fatal("apply:", applied, failed, fmtItem(actions))
+	}
+	items, err := svn.GetPermissions("test", "/branches/dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for i := 0; i < len(items); i++ {
+		if items[i].AccountId == "alice" && !items[i].Inherited && items[i].AccessLevel == model.SvnPermissionNoAccess {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("permissions:", fmtItem(items))
+	}
+
+	applied, failed = copier.Apply(svn, actions)
+	if applied != 0 || failed != 1 || len(actions[0].Error) < 1 {
+		t.Error("duplicate add expected error:", fmtItem(actions))
+	}
+}
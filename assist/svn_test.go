@@ -36,6 +36,17 @@ func TestSvn_GetRepositoryFolders(t *testing.T) {
 	}
 }
 
+func TestSvn_GetRepositoryPath(t *testing.T) {
+	svn := &Svn{}
+	names := []string{"", ".", "..", "..\\..\\x", "../x", "c:x"}
+	for i := 0; i < len(names); i++ {
+		_, err := svn.GetRepositoryPath(names[i])
+		if err == nil {
+			t.Errorf("'%s': error expected", names[i])
+		}
+	}
+}
+
 func TestSvn_GetPermissions(t *testing.T) {
 	svn := &Svn{}
 	items, err := svn.GetPermissions("test", "/trunk")
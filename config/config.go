@@ -60,10 +60,40 @@ func NewConfig() *Config {
 				},
 			},
 		},
+		Dhcp: Dhcp{
+			Concurrency: 4,
+			Alarm: DhcpAlarm{
+				Interval: 10,
+				Warning:  80,
+				Critical: 95,
+				Scopes:   []DhcpAlarmScope{},
+			},
+			History: DhcpHistory{
+				Enable:    false,
+				Interval:  5,
+				Retention: 90,
+			},
+			Rogue: DhcpRogue{
+				Interval: 0,
+				IdleDays: 30,
+			},
+		},
 		Dns: Dns{
 			ZoomNames: []string{},
 		},
 		Svn: Svn{
+			Backend: SvnBackendVisualSvn,
+			Lifecycle: SvnLifecycle{
+				RecentDays: 30,
+			},
+			Templates: []SvnTemplate{
+				{
+					Name:        "standard",
+					Folders:     []string{"/branches", "/tags", "/trunk"},
+					Permissions: []SvnTemplatePermission{},
+					Hooks:       []SvnTemplateHook{},
+				},
+			},
 			Ad: MsAd{
 				Host:     "127.0.0.1",
 				Port:     636,
@@ -2,4 +2,41 @@ package config
 
 type Dhcp struct {
 	Enable bool `json:"enable"`
+
+	OuiFile     string       `json:"ouiFile" note:"IEEE OUI数据库文件路径(oui.txt或oui.csv), 从standards-oui.ieee.org下载, 用于替换内置数据库; 为空时使用内置数据库"`
+	Concurrency int          `json:"concurrency" note:"同时获取地址租用的最大作用域数量, 0表示使用默认值(4)"`
+	Alarm       DhcpAlarm    `json:"alarm"`
+	History     DhcpHistory  `json:"history"`
+	Rogue       DhcpRogue    `json:"rogue"`
+	Failover    DhcpFailover `json:"failover"`
+}
+
+type DhcpFailover struct {
+	AutoReplicate bool `json:"autoReplicate" note:"通过本服务修改筛选器或保留地址后是否立即复制到故障转移伙伴服务器"`
+}
+
+type DhcpRogue struct {
+	Interval int `json:"interval" note:"未知设备检查间隔(分钟), 0表示不检查"`
+	IdleDays int `json:"idleDays" note:"允许列表中超过该天数未租用地址的筛选器视为闲置"`
+}
+
+type DhcpHistory struct {
+	Enable    bool   `json:"enable" note:"是否记录地址租用历史"`
+	Interval  int    `json:"interval" note:"采样间隔(分钟)"`
+	Retention int    `json:"retention" note:"保留天数, 0表示永久保留"`
+	File      string `json:"file" note:"历史记录文件路径, 为空时使用默认路径"`
+}
+
+type DhcpAlarm struct {
+	Interval int     `json:"interval" note:"作用域使用率检查间隔(分钟), 0表示不检查"`
+	Warning  float64 `json:"warning" note:"警告阈值, 使用率(%)"`
+	Critical float64 `json:"critical" note:"严重阈值, 使用率(%)"`
+
+	Scopes []DhcpAlarmScope `json:"scopes" note:"指定作用域的阈值, 覆盖默认阈值"`
+}
+
+type DhcpAlarmScope struct {
+	ScopeId  string  `json:"scopeId" note:"作用域ID"`
+	Warning  float64 `json:"warning" note:"警告阈值, 使用率(%)"`
+	Critical float64 `json:"critical" note:"严重阈值, 使用率(%)"`
 }
@@ -1,7 +1,62 @@
 package config
 
+const (
+	SvnBackendVisualSvn = "visualsvn"
+	SvnBackendAuthz     = "authz"
+)
+
 type Svn struct {
 	Enable bool `json:"enable"`
 
-	Ad MsAd `json:"ad"`
+	Backend   string        `json:"backend" note:"服务类型: visualsvn-VisualSVN Server(默认); authz-svnadmin创建的存储库及authz文件(Apache或svnserve)"`
+	Authz     SvnAuthz      `json:"authz" note:"authz服务配置, 仅backend为authz时有效"`
+	Lifecycle SvnLifecycle  `json:"lifecycle" note:"存储库删除、重命名及归档"`
+	Backup    SvnBackup     `json:"backup" note:"存储库备份"`
+	Templates []SvnTemplate `json:"templates" note:"新建存储库模板"`
+	Hook      SvnHook       `json:"hook" note:"钩子脚本"`
+	Ad        MsAd          `json:"ad"`
+}
+
+type SvnLifecycle struct {
+	RecentDays int    `json:"recentDays" note:"最近提交天数, 该天数内有提交的存储库拒绝删除、重命名及归档(可强制), 0表示不检查"`
+	DumpFolder string `json:"dumpFolder" note:"归档转储文件目录, 为空时使用默认路径"`
+}
+
+type SvnAuthz struct {
+	Root string `json:"root" note:"存储库根目录, 每个子目录为一个存储库"`
+	File string `json:"file" note:"authz访问权限文件路径"`
+	Url  string `json:"url" note:"存储库根地址, 如: https://svn.example.com/svn"`
+}
+
+type SvnBackup struct {
+	Folder string `json:"folder" note:"备份文件目录, 为空时使用默认路径"`
+}
+
+type SvnHook struct {
+	Library string `json:"library" note:"审核通过的钩子脚本库目录, 每个钩子一个子目录(如: pre-commit/commit-message.bat), 为空时使用默认路径"`
+}
+
+type SvnTemplate struct {
+	Name        string                  `json:"name" note:"模板名称, 新建存储库时指定"`
+	Folders     []string                `json:"folders" note:"文件夹, 如: /projA/trunk, 不存在的上级文件夹自动创建; 为空时不创建文件夹"`
+	Permissions []SvnTemplatePermission `json:"permissions" note:"初始访问权限"`
+	Hooks       []SvnTemplateHook       `json:"hooks" note:"默认钩子"`
+	Seed        SvnTemplateSeed         `json:"seed" note:"初始提交, 目录为空时不提交"`
+}
+
+type SvnTemplatePermission struct {
+	Path        string `json:"path" note:"路径, 如: /trunk"`
+	AccountId   string `json:"accountId" note:"账号ID"`
+	AccessLevel int    `json:"accessLevel" note:"访问级别: 0-无权限; 1-只读; 2-读写"`
+}
+
+type SvnTemplateHook struct {
+	Name   string `json:"name" note:"钩子名称, 如: pre-commit"`
+	Script string `json:"script" note:"钩子脚本库(svn.hook.library)中的脚本名称, 不含扩展名, 如: commit-message"`
+}
+
+type SvnTemplateSeed struct {
+	Folder  string `json:"folder" note:"本地目录, 其中的文件及文件夹导入到存储库"`
+	Path    string `json:"path" note:"导入到的存储库路径, 为空时为根路径"`
+	Message string `json:"message" note:"提交日志"`
 }
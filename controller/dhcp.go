@@ -7,7 +7,10 @@ import (
 	"github.com/csby/gwin/model"
 	"github.com/csby/gwsf/gtype"
 	"net"
+	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 func NewDhcp(log gtype.Log, cfg *config.Config) *Dhcp {
@@ -20,6 +23,57 @@ func NewDhcp(log gtype.Log, cfg *config.Config) *Dhcp {
 
 type Dhcp struct {
 	base
+
+	statusMutex sync.RWMutex
+	status      model.DhcpStatus
+	alarmLevels map[string]string
+
+	history *assist.DhcpHistory
+
+	rogueMutex  sync.RWMutex
+	rogueEvents []*model.DhcpRogueEvent
+	rogueKnown  map[string]bool
+}
+
+func (s *Dhcp) StartMonitor() {
+	interval := s.cfg.Dhcp.Alarm.Interval
+	if interval > 0 {
+		go s.monitor(time.Duration(interval) * time.Minute)
+	}
+
+	if s.cfg.Dhcp.History.Enable {
+		s.history = s.loadHistory()
+	}
+	if s.history != nil {
+		interval = s.cfg.Dhcp.History.Interval
+		if interval < 1 {
+			interval = 5
+		}
+		go s.sample(time.Duration(interval) * time.Minute)
+	}
+
+	interval = s.cfg.Dhcp.Rogue.Interval
+	if interval > 0 {
+		go s.inspect(time.Duration(interval) * time.Minute)
+	}
+}
+
+func (s *Dhcp) loadHistory() *assist.DhcpHistory {
+	history := &assist.DhcpHistory{FilePath: s.cfg.Dhcp.History.File}
+	err := history.Load()
+	if err == nil {
+		return history
+	}
+	s.LogError("dhcp: load lease history fail: ", err)
+
+	// a corrupt file has been moved aside, otherwise it is not overwritten by sampling
+	_, err = os.Stat(history.FilePath)
+	if err == nil {
+		s.LogWarning("dhcp: lease history sampling disabled")
+		return nil
+	}
+
+	return history
 }
 
 func (s *Dhcp) GetFilters(ctx gtype.Context, ps gtype.Params) {
@@ -42,6 +96,7 @@ func (s *Dhcp) GetFiltersDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 			Allow:   true,
 			Address: "00-1C-23-20-AF-4A",
 			Comment: "描述信息",
+			Vendor:  "Dell Inc.",
 		},
 	})
 	function.AddOutputError(gtype.ErrInternal)
@@ -59,33 +114,41 @@ func (s *Dhcp) AddFilter(ctx gtype.Context, ps gtype.Params) {
 		ctx.Error(gtype.ErrInput, "MAC地址为空")
 		return
 	}
-	_, err = net.ParseMAC(argument.Address)
+	dhcp := &assist.Dhcp{}
+	address, err := dhcp.NormalizeAddress(argument.Address)
 	if err != nil {
 		ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Address))
 		return
 	}
-	argument.Address = strings.ToUpper(strings.ReplaceAll(argument.Address, ":", "-"))
+	argument.Address = address
 
-	dhcp := &assist.Dhcp{}
 	err = dhcp.AddFilter(argument)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
 	}
 
-	ctx.Success(nil)
+	result := s.getFilterResult(dhcp, argument.Allow)
+	result.Replication = s.replicateFilters(dhcp, []*model.DhcpFilter{argument}, nil, nil)
+
+	ctx.Success(result)
 }
 
 func (s *Dhcp) AddFilterDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	catalog := s.createCatalog(doc, "筛选器")
 	function := catalog.AddFunction(method, uri, "添加筛选器")
-	function.SetNote("添加IPv4筛选器到允许或拒绝列表")
+	function.SetNote("添加IPv4筛选器到允许或拒绝列表; 启用自动复制(dhcp.failover.autoReplicate)时同时修改故障转移伙伴服务器的筛选器")
 	function.SetInputJsonExample(&model.DhcpFilter{
 		Allow:   true,
 		Address: "00-1C-23-20-AF-4A",
 		Comment: "描述信息",
 	})
-	function.SetOutputDataExample(nil)
+	function.SetOutputDataExample(&model.DhcpFilterResult{
+		Warning: "允许列表未启用, 修改不会生效",
+		Replication: &model.DhcpFailoverReplication{
+			Replicated: true,
+		},
+	})
 	function.AddOutputError(gtype.ErrInput)
 	function.AddOutputError(gtype.ErrInternal)
 }
@@ -102,31 +165,50 @@ func (s *Dhcp) DelFilter(ctx gtype.Context, ps gtype.Params) {
 		ctx.Error(gtype.ErrInput, "MAC地址为空")
 		return
 	}
-	_, err = net.ParseMAC(argument.Address)
+	dhcp := &assist.Dhcp{}
+	address, err := dhcp.NormalizeAddress(argument.Address)
 	if err != nil {
 		ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Address))
 		return
 	}
-	argument.Address = strings.ToUpper(strings.ReplaceAll(argument.Address, ":", "-"))
+	argument.Address = address
+
+	allow := false
+	filters, err := dhcp.GetFilters()
+	if err == nil {
+		for i := 0; i < len(filters); i++ {
+			if filters[i].Address == argument.Address {
+				allow = filters[i].Allow
+				break
+			}
+		}
+	}
 
-	dhcp := &assist.Dhcp{}
 	err = dhcp.DeleteFilter(argument.Address)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
 	}
 
-	ctx.Success(nil)
+	result := s.getFilterResult(dhcp, allow)
+	result.Replication = s.replicateFilters(dhcp, nil, nil, []string{argument.Address})
+
+	ctx.Success(result)
 }
 
 func (s *Dhcp) DelFilterDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	catalog := s.createCatalog(doc, "筛选器")
 	function := catalog.AddFunction(method, uri, "删除筛选器")
-	function.SetNote("从IPv4筛选器允许或拒绝列表中删除指定的筛选器")
+	function.SetNote("从IPv4筛选器允许或拒绝列表中删除指定的筛选器; 启用自动复制(dhcp.failover.autoReplicate)时同时修改故障转移伙伴服务器的筛选器")
 	function.SetInputJsonExample(&model.DhcpFilterDelete{
 		Address: "00-1C-23-20-AF-4A",
 	})
-	function.SetOutputDataExample(nil)
+	function.SetOutputDataExample(&model.DhcpFilterResult{
+		Warning: "允许列表未启用, 修改不会生效",
+		Replication: &model.DhcpFailoverReplication{
+			Replicated: true,
+		},
+	})
 	function.AddOutputError(gtype.ErrInput)
 	function.AddOutputError(gtype.ErrInternal)
 }
@@ -147,48 +229,50 @@ func (s *Dhcp) ModFilter(ctx gtype.Context, ps gtype.Params) {
 		ctx.Error(gtype.ErrInput, "新MAC地址为空")
 		return
 	}
-	_, err = net.ParseMAC(argument.Filter.Address)
+	dhcp := &assist.Dhcp{}
+	oldAddr, err := dhcp.NormalizeAddress(argument.Address)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Address))
+		return
+	}
+	newAddr, err := dhcp.NormalizeAddress(argument.Filter.Address)
 	if err != nil {
 		ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Filter.Address))
 		return
 	}
-
-	oldAddr := strings.ToUpper(strings.ReplaceAll(argument.Address, ":", "-"))
-	newAddr := strings.ToUpper(strings.ReplaceAll(argument.Filter.Address, ":", "-"))
 	argument.Filter.Address = newAddr
 
-	dhcp := &assist.Dhcp{}
-	if oldAddr == newAddr {
-		err = dhcp.DeleteFilter(oldAddr)
-		if err != nil {
-			ctx.Error(gtype.ErrInternal.SetDetail(err))
-			return
-		}
-		err = dhcp.AddFilter(&argument.Filter)
-		if err != nil {
-			ctx.Error(gtype.ErrInternal.SetDetail(err))
-			return
-		}
-	} else {
-		err = dhcp.AddFilter(&argument.Filter)
-		if err != nil {
-			ctx.Error(gtype.ErrInternal.SetDetail(err))
+	filters, err := dhcp.GetFilters()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	result, err := dhcp.ModifyFilter(filters, oldAddr, &argument.Filter)
+	if err != nil {
+		if result == nil {
+			ctx.Error(gtype.ErrInput, err)
 			return
 		}
-		err = dhcp.DeleteFilter(argument.Address)
-		if err != nil {
-			ctx.Error(gtype.ErrInternal.SetDetail(err))
-			return
+		ctx.Error(gtype.ErrInternal.SetDetail(fmt.Errorf("%v, 服务器筛选器状态: %s", err, result.State)))
+		return
+	}
+	result.DhcpFilterResult = *s.getFilterResult(dhcp, argument.Filter.Allow)
+	if result.State == model.DhcpFilterStateModified {
+		changed := []*model.DhcpFilter{&argument.Filter}
+		if oldAddr == newAddr {
+			result.Replication = s.replicateFilters(dhcp, nil, changed, nil)
+		} else {
+			result.Replication = s.replicateFilters(dhcp, changed, nil, []string{oldAddr})
 		}
 	}
 
-	ctx.Success(nil)
+	ctx.Success(result)
 }
 
 func (s *Dhcp) ModFilterDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	catalog := s.createCatalog(doc, "筛选器")
 	function := catalog.AddFunction(method, uri, "修改筛选器")
-	function.SetNote("修改IPv4筛选器允许或拒绝列表中已存在的筛选器")
+	function.SetNote("修改IPv4筛选器允许或拒绝列表中已存在的筛选器, 失败时自动恢复原筛选器, 错误详情中的state表示服务器筛选器状态; 启用自动复制(dhcp.failover.autoReplicate)时同时修改故障转移伙伴服务器的筛选器")
 	function.SetInputJsonExample(&model.DhcpFilterModify{
 		Address: "00-1C-23-20-AF-4A",
 		Filter: model.DhcpFilter{
@@ -197,36 +281,514 @@ func (s *Dhcp) ModFilterDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 			Comment: "描述信息",
 		},
 	})
-	function.SetOutputDataExample(nil)
+	function.SetOutputDataExample(&model.DhcpFilterModifyResult{
+		DhcpFilterResult: model.DhcpFilterResult{
+			Warning: "允许列表未启用, 修改不会生效",
+			Replication: &model.DhcpFailoverReplication{
+				Replicated: true,
+			},
+		},
+		State: model.DhcpFilterStateModified,
+		Original: &model.DhcpFilter{
+			Allow:   true,
+			Address: "00-1C-23-20-AF-4A",
+			Comment: "描述信息",
+		},
+	})
 	function.AddOutputError(gtype.ErrInput)
 	function.AddOutputError(gtype.ErrInternal)
 }
 
-func (s *Dhcp) GetLeases(ctx gtype.Context, ps gtype.Params) {
+func (s *Dhcp) GetFilterList(ctx gtype.Context, ps gtype.Params) {
+	dhcp := &assist.Dhcp{}
+	result, err := dhcp.GetFilterList()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) GetFilterListDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "筛选器")
+	function := catalog.AddFunction(method, uri, "获取筛选器启用状态")
+	function.SetNote("获取IPv4允许列表和拒绝列表是否启用")
+	function.SetOutputDataExample(&model.DhcpFilterList{
+		Allow: false,
+		Deny:  true,
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) SetFilterList(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpFilterListArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if argument.Allow == nil && argument.Deny == nil {
+		ctx.Error(gtype.ErrInput, "允许列表(allow)和拒绝列表(deny)均为空")
+		return
+	}
+
 	dhcp := &assist.Dhcp{}
-	results, err := dhcp.GetLeases()
+	list, err := dhcp.GetFilterList()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	if argument.Allow != nil {
+		list.Allow = *argument.Allow
+	}
+	if argument.Deny != nil {
+		list.Deny = *argument.Deny
+	}
+	err = dhcp.SetFilterList(list)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
 	}
 
+	ctx.Success(list)
+}
+
+func (s *Dhcp) SetFilterListDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "筛选器")
+	function := catalog.AddFunction(method, uri, "设置筛选器启用状态")
+	function.SetNote("启用或禁用IPv4允许列表和拒绝列表, 未指定的列表保持当前状态不变")
+	deny := true
+	function.SetInputJsonExample(&model.DhcpFilterListArgument{
+		Deny: &deny,
+	})
+	function.SetOutputDataExample(&model.DhcpFilterList{
+		Allow: false,
+		Deny:  true,
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) LoadOui() {
+	dhcp := &assist.Dhcp{}
+	if len(s.cfg.Dhcp.OuiFile) < 1 {
+		oui := dhcp.GetOui()
+		s.LogInfo(fmt.Sprintf("dhcp: %d vendors loaded from %s", oui.Count, oui.Source))
+		return
+	}
+
+	oui, err := dhcp.LoadOui(s.cfg.Dhcp.OuiFile)
+	if err != nil {
+		s.LogError("dhcp: load oui database fail, the built-in one is used: ", err)
+		return
+	}
+	s.LogInfo(fmt.Sprintf("dhcp: %d vendors loaded from %s", oui.Count, oui.Source))
+}
+
+func (s *Dhcp) ReloadOui(ctx gtype.Context, ps gtype.Params) {
+	if len(s.cfg.Dhcp.OuiFile) < 1 {
+		ctx.Error(gtype.ErrInput, "未配置OUI数据库文件路径(dhcp.ouiFile)")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	result, err := dhcp.LoadOui(s.cfg.Dhcp.OuiFile)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) ReloadOuiDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "地址租用")
+	function := catalog.AddFunction(method, uri, "重新加载OUI数据库")
+	function.SetNote("从配置的本地文件(dhcp.ouiFile)重新加载网卡厂商数据库替换内置数据库, 支持IEEE发布的oui.txt和oui.csv格式")
+	function.SetOutputDataExample(&model.DhcpOui{
+		Source: "C:\\gwin\\cfg\\oui.txt",
+		Count:  32000,
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetLeases(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpLeaseArgument{}
+	ctx.GetJson(argument)
+	err := s.checkFamily(argument.Family)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	results := &model.DhcpLeaseList{
+		Scopes: make([]*model.DhcpScopeLeases, 0),
+	}
+	if argument.Family != model.DhcpFamilyIpV6 {
+		list, le := dhcp.GetScopeLeases()
+		if le != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(le))
+			return
+		}
+		results.Scopes = append(results.Scopes, list.Scopes...)
+		results.FilterError = list.FilterError
+	}
+	if argument.Family != model.DhcpFamilyIpV4 {
+		list, le := dhcp.GetV6ScopeLeases()
+		if le != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(le))
+			return
+		}
+		results.Scopes = append(results.Scopes, list.Scopes...)
+	}
+
+	if len(argument.Vendor) > 0 {
+		vendor := strings.ToLower(argument.Vendor)
+		for si := 0; si < len(results.Scopes); si++ {
+			scope := results.Scopes[si]
+			items := make([]*model.DhcpLease, 0)
+			for i := 0; i < len(scope.Leases); i++ {
+				if strings.Contains(strings.ToLower(scope.Leases[i].Vendor), vendor) {
+					items = append(items, scope.Leases[i])
+				}
+			}
+			scope.Leases = items
+		}
+	}
+
 	ctx.Success(results)
 }
 
 func (s *Dhcp) GetLeasesDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	catalog := s.createCatalog(doc, "地址租用")
 	function := catalog.AddFunction(method, uri, "获取地址租用列表")
-	function.SetNote("获取IPv4地址租用列表")
-	function.SetOutputDataExample([]*model.DhcpLease{
-		{
-			IpV4:    "192.168.1.103",
-			Address: "00-1C-23-20-AF-4A",
-			Comment: "描述信息",
+	function.SetNote("获取IPv4和IPv6地址租用列表, 按作用域分组返回, 可按地址族或网卡厂商筛选; 单个作用域获取失败时在该作用域的error中返回错误信息, 其余作用域正常返回")
+	function.SetInputJsonExample(&model.DhcpLeaseArgument{
+		DhcpFamilyArgument: model.DhcpFamilyArgument{
+			Family: model.DhcpFamilyIpV4,
 		},
+		Vendor: "Dell",
+	})
+	function.SetOutputDataExample(&model.DhcpLeaseList{
+		Scopes: []*model.DhcpScopeLeases{
+			{
+				Family:  model.DhcpFamilyIpV4,
+				ScopeId: "192.168.1.0",
+				Leases: []*model.DhcpLease{
+					{
+						Family:          model.DhcpFamilyIpV4,
+						ScopeId:         "192.168.1.0",
+						IpV4:            "192.168.1.103",
+						Address:         "00-1C-23-20-AF-4A",
+						Vendor:          "Dell Inc.",
+						HostName:        "pc-01.example.com",
+						LeaseExpiryTime: "2020-10-19 08:30:00",
+						AddressState:    "Active",
+						ClientType:      "Dhcp",
+						Description:     "",
+						Comment:         "描述信息",
+					},
+				},
+			},
+			{
+				Family:  model.DhcpFamilyIpV4,
+				ScopeId: "192.168.2.0",
+				Error:   "Get-DhcpServerv4Lease : Failed to get leases in scope 192.168.2.0. Access is denied.",
+				Leases:  []*model.DhcpLease{},
+			},
+		},
+		FilterError: "",
 	})
 	function.AddOutputError(gtype.ErrInternal)
 }
 
+func (s *Dhcp) DelLease(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpLeaseDelete{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	if len(argument.IpV4) < 1 && len(argument.Address) < 1 {
+		ctx.Error(gtype.ErrInput, "IPv4地址(ipV4)和MAC地址(address)均为空")
+		return
+	}
+	if len(argument.IpV4) > 0 {
+		if net.ParseIP(argument.IpV4).To4() == nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("IPv4地址(%s)无效", argument.IpV4))
+			return
+		}
+	}
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	if len(argument.Address) > 0 {
+		address, ae := dhcp.NormalizeAddress(argument.Address)
+		if ae != nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Address))
+			return
+		}
+		argument.Address = address
+	}
+
+	list, err := dhcp.GetScopeLeases()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	leases, scopeErrors := dhcp.FlattenLeases(list)
+	results := make([]*model.DhcpLease, 0)
+	for i := 0; i < len(leases); i++ {
+		lease := leases[i]
+		if len(argument.IpV4) > 0 && lease.IpV4 != argument.IpV4 {
+			continue
+		}
+		if len(argument.Address) > 0 && lease.Address != argument.Address {
+			continue
+		}
+		results = append(results, lease)
+	}
+	if len(results) < 1 {
+		if len(scopeErrors) > 0 {
+			ctx.Error(gtype.ErrInternal.SetDetail(strings.Join(scopeErrors, "; ")))
+			return
+		}
+		ctx.Error(gtype.ErrInput, "租用不存在")
+		return
+	}
+
+	if argument.Deny {
+		err = s.denyLeases(dhcp, results, argument.Comment)
+		if err != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(err))
+			return
+		}
+	}
+
+	err = s.deleteLeases(dhcp, results)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(&model.DhcpLeaseDeleteResult{
+		Leases:      results,
+		ScopeErrors: scopeErrors,
+	})
+}
+
+func (s *Dhcp) DelLeaseDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "地址租用")
+	function := catalog.AddFunction(method, uri, "删除地址租用")
+	function.SetNote("按IPv4地址或MAC地址释放并删除地址租用, 可同时将MAC地址添加到拒绝列表, 成功时返回已删除的租用列表; 部分作用域获取租用失败时其余作用域正常处理, 失败信息在scopeErrors中返回")
+	function.SetInputJsonExample(&model.DhcpLeaseDelete{
+		IpV4:    "192.168.1.103",
+		Address: "",
+		Deny:    true,
+		Comment: "已重装",
+	})
+	function.SetOutputDataExample(&model.DhcpLeaseDeleteResult{
+		Leases: []*model.DhcpLease{
+			{
+				ScopeId:      "192.168.1.0",
+				IpV4:         "192.168.1.103",
+				Address:      "00-1C-23-20-AF-4A",
+				HostName:     "pc-01.example.com",
+				AddressState: "Active",
+				ClientType:   "Dhcp",
+			},
+		},
+		ScopeErrors: []string{},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) DelLeases(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpLeaseDeleteBatch{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	if len(argument.ScopeId) < 1 && !argument.Denied {
+		ctx.Error(gtype.ErrInput, "作用域ID(scopeId)为空")
+		return
+	}
+
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	list, err := dhcp.GetScopeLeases()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	for i := 0; i < len(list.Scopes); i++ {
+		scope := list.Scopes[i]
+		if scope.ScopeId == argument.ScopeId && len(scope.Error) > 0 {
+			ctx.Error(gtype.ErrInternal.SetDetail(scope.Error))
+			return
+		}
+	}
+	leases, scopeErrors := dhcp.FlattenLeases(list)
+	if len(argument.ScopeId) > 0 {
+		scopeErrors = make([]string, 0)
+	}
+
+	denied := make(map[string]bool)
+	if argument.Denied {
+		filters, fe := dhcp.GetFilters()
+		if fe != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(fe))
+			return
+		}
+		for i := 0; i < len(filters); i++ {
+			filter := filters[i]
+			if filter.Allow {
+				continue
+			}
+			denied[filter.Address] = true
+		}
+	}
+
+	results := make([]*model.DhcpLease, 0)
+	for i := 0; i < len(leases); i++ {
+		lease := leases[i]
+		if len(argument.ScopeId) > 0 && lease.ScopeId != argument.ScopeId {
+			continue
+		}
+		if argument.Denied && !denied[lease.Address] {
+			continue
+		}
+		results = append(results, lease)
+	}
+
+	if len(results) > 0 {
+		err = s.deleteLeases(dhcp, results)
+		if err != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(err))
+			return
+		}
+	}
+
+	ctx.Success(&model.DhcpLeaseDeleteResult{
+		Leases:      results,
+		ScopeErrors: scopeErrors,
+	})
+}
+
+func (s *Dhcp) DelLeasesDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "地址租用")
+	function := catalog.AddFunction(method, uri, "批量删除地址租用")
+	function.SetNote("按作用域或拒绝列表批量释放并删除地址租用, 成功时返回已删除的租用列表; 不指定作用域时, 获取租用失败的作用域被跳过并在scopeErrors中返回")
+	function.SetInputJsonExample(&model.DhcpLeaseDeleteBatch{
+		ScopeId: "192.168.1.0",
+		Denied:  true,
+	})
+	function.SetOutputDataExample(&model.DhcpLeaseDeleteResult{
+		Leases: []*model.DhcpLease{
+			{
+				ScopeId:      "192.168.1.0",
+				IpV4:         "192.168.1.103",
+				Address:      "00-1C-23-20-AF-4A",
+				HostName:     "pc-01.example.com",
+				AddressState: "Active",
+				ClientType:   "Dhcp",
+			},
+		},
+		ScopeErrors: []string{},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) deleteLeases(dhcp *assist.Dhcp, leases []*model.DhcpLease) error {
+	addresses := make([]string, 0)
+	for i := 0; i < len(leases); i++ {
+		addresses = append(addresses, leases[i].IpV4)
+	}
+
+	return dhcp.DeleteLeases(addresses...)
+}
+
+func (s *Dhcp) denyLeases(dhcp *assist.Dhcp, leases []*model.DhcpLease, comment string) error {
+	filters, err := dhcp.GetFilters()
+	if err != nil {
+		return err
+	}
+	existed := make(map[string]*model.DhcpFilter)
+	for i := 0; i < len(filters); i++ {
+		filter := filters[i]
+		existed[filter.Address] = filter
+	}
+
+	for i := 0; i < len(leases); i++ {
+		address := leases[i].Address
+		filter, ok := existed[address]
+		if ok {
+			if !filter.Allow {
+				continue
+			}
+			err = dhcp.DeleteFilter(address)
+			if err != nil {
+				return err
+			}
+		}
+
+		filter = &model.DhcpFilter{
+			Allow:   false,
+			Address: address,
+			Comment: comment,
+		}
+		err = dhcp.AddFilter(filter)
+		if err != nil {
+			return err
+		}
+		existed[address] = filter
+	}
+
+	return nil
+}
+
+func (s *Dhcp) getFilterResult(dhcp *assist.Dhcp, allow bool) *model.DhcpFilterResult {
+	result := &model.DhcpFilterResult{}
+	list, err := dhcp.GetFilterList()
+	if err != nil {
+		return result
+	}
+
+	if allow && !list.Allow {
+		result.Warning = "允许列表未启用, 修改不会生效"
+	} else if !allow && !list.Deny {
+		result.Warning = "拒绝列表未启用, 修改不会生效"
+	}
+
+	return result
+}
+
+func (s *Dhcp) checkFamily(family string) error {
+	if len(family) < 1 {
+		return nil
+	}
+	if family != model.DhcpFamilyIpV4 && family != model.DhcpFamilyIpV6 {
+		return fmt.Errorf("地址族(%s)无效", family)
+	}
+
+	return nil
+}
+
+func (s *Dhcp) monitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.checkScopeStatistics()
+	for range ticker.C {
+		s.checkScopeStatistics()
+	}
+}
+
 func (s *Dhcp) createCatalog(doc gtype.Doc, names ...string) gtype.Catalog {
 	root := s.createRootCatalog(doc, "DHCP")
 	count := len(names)
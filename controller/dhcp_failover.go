@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"net"
+	"strings"
+)
+
+func (s *Dhcp) GetFailovers(ctx gtype.Context, ps gtype.Params) {
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetFailovers()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetFailoversDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "故障转移")
+	function := catalog.AddFunction(method, uri, "获取故障转移关系列表")
+	function.SetNote("获取IPv4故障转移关系, 包括伙伴服务器、模式、状态及包含的作用域")
+	function.SetOutputDataExample([]*model.DhcpFailover{
+		{
+			Name:                "dhcp1-dhcp2",
+			PartnerServer:       "dhcp2.example.com",
+			Mode:                "LoadBalance",
+			State:               "Normal",
+			LoadBalancePercent:  50,
+			MaxClientLeadTime:   "01:00:00",
+			AutoStateTransition: false,
+			ScopeIds:            []string{"192.168.1.0", "192.168.2.0"},
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) ReplicateFailover(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpFailoverReplicate{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	if len(argument.Name) < 1 && len(argument.ScopeId) < 1 {
+		ctx.Error(gtype.ErrInput, "故障转移关系名称(name)和作用域ID(scopeId)均为空")
+		return
+	}
+	if len(argument.Name) > 0 && len(argument.ScopeId) > 0 {
+		ctx.Error(gtype.ErrInput, "故障转移关系名称(name)和作用域ID(scopeId)只能指定一个")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	if len(argument.ScopeId) > 0 {
+		if net.ParseIP(argument.ScopeId).To4() == nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("作用域ID(%s)无效", argument.ScopeId))
+			return
+		}
+		err = dhcp.ReplicateScopes(argument.ScopeId)
+	} else {
+		err = dhcp.ReplicateFailover(argument.Name)
+	}
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(nil)
+}
+
+func (s *Dhcp) ReplicateFailoverDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "故障转移")
+	function := catalog.AddFunction(method, uri, "复制故障转移配置")
+	function.SetNote("将指定作用域或故障转移关系中所有作用域的配置复制到伙伴服务器")
+	function.SetInputJsonExample(&model.DhcpFailoverReplicate{
+		Name: "dhcp1-dhcp2",
+	})
+	function.SetOutputDataExample(nil)
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) replicate(dhcp *assist.Dhcp, scopeId string) *model.DhcpFailoverReplication {
+	if !s.cfg.Dhcp.Failover.AutoReplicate {
+		return nil
+	}
+
+	result := &model.DhcpFailoverReplication{}
+	failover, err := dhcp.GetScopeFailover(scopeId)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if failover == nil {
+		return nil
+	}
+	err = dhcp.ReplicateScopes(scopeId)
+	if err != nil {
+		result.Error = err.Error()
+		s.LogWarning(fmt.Sprintf("dhcp: replicate scope %s to %s fail: %v", scopeId, failover.PartnerServer, err))
+		return result
+	}
+	result.Replicated = true
+
+	return result
+}
+
+func (s *Dhcp) replicateFilters(dhcp *assist.Dhcp, added, updated []*model.DhcpFilter, removed []string) *model.DhcpFailoverReplication {
+	if !s.cfg.Dhcp.Failover.AutoReplicate {
+		return nil
+	}
+	if len(added)+len(updated)+len(removed) < 1 {
+		return nil
+	}
+
+	result := &model.DhcpFailoverReplication{}
+	failovers, err := dhcp.GetFailovers()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	partners := make([]string, 0)
+	existed := make(map[string]bool)
+	for i := 0; i < len(failovers); i++ {
+		partner := failovers[i].PartnerServer
+		if len(partner) < 1 || existed[strings.ToLower(partner)] {
+			continue
+		}
+		existed[strings.ToLower(partner)] = true
+		partners = append(partners, partner)
+	}
+	if len(partners) < 1 {
+		return nil
+	}
+
+	errs := make([]string, 0)
+	for i := 0; i < len(partners); i++ {
+		partner := partners[i]
+		for j := 0; j < len(removed); j++ {
+			err = dhcp.DeletePartnerFilter(partner, removed[j])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("delete %s on %s: %v", removed[j], partner, err))
+			}
+		}
+		for j := 0; j < len(updated); j++ {
+			de := dhcp.DeletePartnerFilter(partner, updated[j].Address)
+			err = dhcp.AddPartnerFilter(partner, updated[j])
+			if err != nil {
+				if de != nil {
+					err = fmt.Errorf("%v; delete fail: %v", err, de)
+				}
+				errs = append(errs, fmt.Sprintf("update %s on %s: %v", updated[j].Address, partner, err))
+			}
+		}
+		for j := 0; j < len(added); j++ {
+			err = dhcp.AddPartnerFilter(partner, added[j])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("add %s on %s: %v", added[j].Address, partner, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		result.Error = strings.Join(errs, "; ")
+		s.LogWarning(fmt.Sprintf("dhcp: replicate filters fail: %s", result.Error))
+		return result
+	}
+	result.Replicated = true
+
+	return result
+}
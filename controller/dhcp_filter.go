@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+)
+
+func (s *Dhcp) ExportFilters(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpFilterExport{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Format) > 0 &&
+		argument.Format != model.DhcpFilterFormatCsv &&
+		argument.Format != model.DhcpFilterFormatJson {
+		ctx.Error(gtype.ErrInput, "文件格式(format)无效")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	result, err := dhcp.ExportFilters(argument.Format)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) ExportFiltersDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "筛选器")
+	function := catalog.AddFunction(method, uri, "导出筛选器")
+	function.SetNote("将IPv4允许列表和拒绝列表导出为csv或json文件内容")
+	function.SetInputJsonExample(&model.DhcpFilterExport{
+		Format: model.DhcpFilterFormatCsv,
+	})
+	function.SetOutputDataExample(&model.DhcpFilterFile{
+		Format:  model.DhcpFilterFormatCsv,
+		Content: "allow,address,comment\ntrue,00-1C-23-20-AF-4A,描述信息\n",
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) ImportFilters(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpFilterImport{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Content) < 1 {
+		ctx.Error(gtype.ErrInput, "文件内容(content)为空")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	targets, err := dhcp.ParseFilters(argument.Format, argument.Content)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	result, err := dhcp.ImportFilters(targets, argument.Remove, argument.DryRun)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	if !result.DryRun {
+		removed := make([]string, 0)
+		for i := 0; i < len(result.Removed); i++ {
+			removed = append(removed, result.Removed[i].Address)
+		}
+		result.Replication = s.replicateFilters(dhcp, result.Added, result.Updated, removed)
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) ImportFiltersDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "筛选器")
+	function := catalog.AddFunction(method, uri, "导入筛选器")
+	function.SetNote("将文件内容与当前筛选器比较: 添加缺少的筛选器, 更新描述或列表不同的筛选器, remove为true时删除文件中不存在的筛选器; dryRun为true时只返回差异; 启用自动复制(dhcp.failover.autoReplicate)时将已执行的变更推送到故障转移伙伴服务器")
+	function.SetInputJsonExample(&model.DhcpFilterImport{
+		DhcpFilterFile: model.DhcpFilterFile{
+			Format:  model.DhcpFilterFormatCsv,
+			Content: "allow,address,comment\ntrue,00-1C-23-20-AF-4A,描述信息\n",
+		},
+		Remove: false,
+		DryRun: true,
+	})
+	function.SetOutputDataExample(&model.DhcpFilterImportResult{
+		DryRun: true,
+		Added: []*model.DhcpFilter{
+			{
+				Allow:   true,
+				Address: "00-1C-23-20-AF-4A",
+				Comment: "描述信息",
+			},
+		},
+		Updated:   []*model.DhcpFilter{},
+		Removed:   []*model.DhcpFilter{},
+		Unchanged: 10,
+		Errors:    []string{},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
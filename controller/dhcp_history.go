@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"time"
+)
+
+func (s *Dhcp) GetLeaseHistory(ctx gtype.Context, ps gtype.Params) {
+	if s.history == nil {
+		ctx.Error(gtype.ErrInternal.SetDetail("未启用地址租用历史记录(dhcp.history.enable)"))
+		return
+	}
+
+	argument := &model.DhcpLeaseHistoryFilter{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	results, err := s.history.Query(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetLeaseHistoryDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "地址租用")
+	function := catalog.AddFunction(method, uri, "获取地址租用历史")
+	function.SetNote("按IPv4地址、MAC地址或时间范围查询定时采样记录的地址租用区间")
+	function.SetInputJsonExample(&model.DhcpLeaseHistoryFilter{
+		IpV4:      "192.168.1.103",
+		StartTime: "2020-10-13 00:00:00",
+		EndTime:   "2020-10-14 00:00:00",
+	})
+	function.SetOutputDataExample([]*model.DhcpLeaseRecord{
+		{
+			ScopeId:   "192.168.1.0",
+			IpV4:      "192.168.1.103",
+			Address:   "00-1C-23-20-AF-4A",
+			HostName:  "pc-01.example.com",
+			StartTime: "2020-10-12 08:05:00",
+			EndTime:   "2020-10-13 18:20:00",
+			Active:    false,
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) sample(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.sampleLeases(interval)
+	for range ticker.C {
+		s.sampleLeases(interval)
+	}
+}
+
+func (s *Dhcp) sampleLeases(interval time.Duration) {
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	list, err := dhcp.GetScopeLeases()
+	if err != nil {
+		s.LogError("dhcp: sample leases fail: ", err)
+		return
+	}
+	leases, scopeErrors := dhcp.FlattenLeases(list)
+	for i := 0; i < len(scopeErrors); i++ {
+		s.LogWarning(fmt.Sprintf("dhcp: sample leases fail: %s", scopeErrors[i]))
+	}
+
+	now := time.Now()
+	s.history.Record(leases, dhcp.FailedScopes(list), now, 2*interval)
+	retention := s.cfg.Dhcp.History.Retention
+	if retention > 0 {
+		count := s.history.Prune(now.AddDate(0, 0, -retention))
+		if count > 0 {
+			s.LogInfo(fmt.Sprintf("dhcp: %d lease history records older than %d days removed", count, retention))
+		}
+	}
+
+	err = s.history.Save()
+	if err != nil {
+		s.LogError("dhcp: save lease history fail: ", err)
+	}
+}
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"net"
+)
+
+func (s *Dhcp) GetOptions(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpOptionArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkOptionArgument(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetOptions(argument.ScopeId, argument.ReservedIp)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetOptionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "选项")
+	function := catalog.AddFunction(method, uri, "获取选项列表")
+	function.SetNote("获取服务器、作用域或保留地址级别上直接设置的IPv4选项")
+	function.SetInputJsonExample(&model.DhcpOptionArgument{
+		ScopeId: "192.168.1.0",
+	})
+	function.SetOutputDataExample([]*model.DhcpOption{
+		{
+			OptionId: model.DhcpOptionDnsServers,
+			Name:     "DNS Servers",
+			Type:     "IPv4Address",
+			Value:    []string{"192.168.1.2", "192.168.1.3"},
+			Level:    model.DhcpOptionLevelScope,
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetEffectiveOptions(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpOptionArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkOptionArgument(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetEffectiveOptions(argument.ScopeId, argument.ReservedIp)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetEffectiveOptionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "选项")
+	function := catalog.AddFunction(method, uri, "获取生效选项列表")
+	function.SetNote("合并服务器、作用域和保留地址级别的IPv4选项, 下级覆盖上级, level表示生效值的来源级别")
+	function.SetInputJsonExample(&model.DhcpOptionArgument{
+		ScopeId:    "192.168.1.0",
+		ReservedIp: "192.168.1.20",
+	})
+	function.SetOutputDataExample([]*model.DhcpOption{
+		{
+			OptionId: model.DhcpOptionRouter,
+			Name:     "Router",
+			Type:     "IPv4Address",
+			Value:    []string{"192.168.1.1"},
+			Level:    model.DhcpOptionLevelScope,
+		},
+		{
+			OptionId: model.DhcpOptionBootFileName,
+			Name:     "Bootfile Name",
+			Type:     "String",
+			Value:    []string{"boot\\x64\\wdsnbp.com"},
+			Level:    model.DhcpOptionLevelReservation,
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) SetOption(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpOptionEdit{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkOptionArgument(&argument.DhcpOptionArgument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if argument.OptionId < 1 || argument.OptionId > 254 {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("选项ID(%d)无效", argument.OptionId))
+		return
+	}
+	if len(argument.Value) < 1 {
+		ctx.Error(gtype.ErrInput, "选项值(value)为空")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	err = dhcp.SetOption(argument.ScopeId, argument.ReservedIp, &model.DhcpOption{
+		OptionId:    argument.OptionId,
+		Value:       argument.Value,
+		VendorClass: argument.VendorClass,
+	})
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(nil)
+}
+
+func (s *Dhcp) SetOptionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "选项")
+	function := catalog.AddFunction(method, uri, "设置选项")
+	function.SetNote("在服务器、作用域或保留地址级别上设置IPv4选项值")
+	function.SetInputJsonExample(&model.DhcpOptionEdit{
+		DhcpOptionArgument: model.DhcpOptionArgument{
+			ScopeId: "192.168.1.0",
+		},
+		OptionId: model.DhcpOptionBootServerName,
+		Value:    []string{"192.168.1.5"},
+	})
+	function.SetOutputDataExample(nil)
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) DelOption(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpOptionDelete{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkOptionArgument(&argument.DhcpOptionArgument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if argument.OptionId < 1 || argument.OptionId > 254 {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("选项ID(%d)无效", argument.OptionId))
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	err = dhcp.DeleteOption(argument.ScopeId, argument.ReservedIp, argument.OptionId, argument.VendorClass)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(nil)
+}
+
+func (s *Dhcp) DelOptionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "选项")
+	function := catalog.AddFunction(method, uri, "删除选项")
+	function.SetNote("删除服务器、作用域或保留地址级别上设置的IPv4选项值")
+	function.SetInputJsonExample(&model.DhcpOptionDelete{
+		DhcpOptionArgument: model.DhcpOptionArgument{
+			ScopeId: "192.168.1.0",
+		},
+		OptionId: model.DhcpOptionBootServerName,
+	})
+	function.SetOutputDataExample(nil)
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetClasses(ctx gtype.Context, ps gtype.Params) {
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetClasses()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetClassesDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "选项")
+	function := catalog.AddFunction(method, uri, "获取类列表")
+	function.SetNote("获取IPv4供应商类和用户类列表")
+	function.SetOutputDataExample([]*model.DhcpClass{
+		{
+			Name:        "PXEClient",
+			Type:        "Vendor",
+			Data:        "PXEClient",
+			Description: "PXE启动客户端",
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) checkOptionArgument(argument *model.DhcpOptionArgument) error {
+	if len(argument.ScopeId) > 0 {
+		if net.ParseIP(argument.ScopeId).To4() == nil {
+			return fmt.Errorf("作用域ID(%s)无效", argument.ScopeId)
+		}
+	}
+	if len(argument.ReservedIp) > 0 {
+		if net.ParseIP(argument.ReservedIp).To4() == nil {
+			return fmt.Errorf("保留地址(%s)无效", argument.ReservedIp)
+		}
+	}
+
+	return nil
+}
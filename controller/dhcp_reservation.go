@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var dhcpDuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{2}(-[0-9a-fA-F]{2})*$`)
+
+func (s *Dhcp) GetReservations(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpReservationArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkFamily(argument.Family)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	scopes := make([]*model.DhcpScope, 0)
+	if len(argument.ScopeId) > 0 {
+		family := argument.Family
+		if len(family) < 1 {
+			family = model.DhcpFamilyIpV4
+			if strings.Contains(argument.ScopeId, ":") {
+				family = model.DhcpFamilyIpV6
+			}
+		}
+		err = s.checkScopeId(family, argument.ScopeId)
+		if err != nil {
+			ctx.Error(gtype.ErrInput, err)
+			return
+		}
+		scopes = append(scopes, &model.DhcpScope{
+			Family:  family,
+			ScopeId: argument.ScopeId,
+		})
+	} else {
+		scopes, err = s.getScopes(dhcp, argument.Family)
+		if err != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(err))
+			return
+		}
+	}
+
+	results := make([]*model.DhcpReservation, 0)
+	for i := 0; i < len(scopes); i++ {
+		scope := scopes[i]
+		var items []*model.DhcpReservation
+		if scope.Family == model.DhcpFamilyIpV6 {
+			items, err = dhcp.GetV6Reservations(scope.ScopeId)
+		} else {
+			items, err = dhcp.GetReservations(scope.ScopeId)
+		}
+		if err != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(err))
+			return
+		}
+		results = append(results, items...)
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetReservationsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "保留地址")
+	function := catalog.AddFunction(method, uri, "获取保留地址列表")
+	function.SetNote("获取IPv4和IPv6保留地址列表, 可按地址族或作用域筛选")
+	function.SetInputJsonExample(&model.DhcpReservationArgument{
+		DhcpFamilyArgument: model.DhcpFamilyArgument{
+			Family: model.DhcpFamilyIpV6,
+		},
+		ScopeId: "2001:db8:1::",
+	})
+	function.SetOutputDataExample([]*model.DhcpReservation{
+		{
+			Family:     model.DhcpFamilyIpV6,
+			ScopeId:    "2001:db8:1::",
+			IpV6:       "2001:db8:1::20",
+			ClientDuid: "00-01-00-01-26-6a-2b-3c-90-94-97-8b-f5-f8",
+			Iaid:       "144741523",
+			Name:       "printer",
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) AddReservation(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpReservation{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.ScopeId) < 1 {
+		ctx.Error(gtype.ErrInput, "作用域ID(scopeId)为空")
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	if len(argument.IpV6) > 0 {
+		err = s.checkScopeId(model.DhcpFamilyIpV6, argument.ScopeId)
+		if err != nil {
+			ctx.Error(gtype.ErrInput, err)
+			return
+		}
+		ip := net.ParseIP(argument.IpV6)
+		if ip == nil || ip.To4() != nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("IPv6地址(%s)无效", argument.IpV6))
+			return
+		}
+		if len(argument.ClientDuid) < 1 {
+			ctx.Error(gtype.ErrInput, "客户端DUID(clientDuid)为空")
+			return
+		}
+		if !dhcpDuidPattern.MatchString(argument.ClientDuid) {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("客户端DUID(%s)无效", argument.ClientDuid))
+			return
+		}
+		if len(argument.Iaid) < 1 {
+			ctx.Error(gtype.ErrInput, "标识关联ID(iaid)为空")
+			return
+		}
+		_, err = strconv.ParseUint(argument.Iaid, 10, 32)
+		if err != nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("标识关联ID(%s)无效", argument.Iaid))
+			return
+		}
+		argument.Family = model.DhcpFamilyIpV6
+		err = dhcp.AddV6Reservation(argument)
+	} else {
+		err = s.checkScopeId(model.DhcpFamilyIpV4, argument.ScopeId)
+		if err != nil {
+			ctx.Error(gtype.ErrInput, err)
+			return
+		}
+		if net.ParseIP(argument.IpV4).To4() == nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("IPv4地址(%s)无效", argument.IpV4))
+			return
+		}
+		address, ae := dhcp.NormalizeAddress(argument.Address)
+		if ae != nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("MAC地址(%s)无效", argument.Address))
+			return
+		}
+		argument.Address = address
+		argument.Family = model.DhcpFamilyIpV4
+		err = dhcp.AddReservation(argument)
+	}
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	result := &model.DhcpReservationResult{}
+	if argument.Family == model.DhcpFamilyIpV4 {
+		result.Replication = s.replicate(dhcp, argument.ScopeId)
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) AddReservationDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "保留地址")
+	function := catalog.AddFunction(method, uri, "添加保留地址")
+	function.SetNote("ipV6不为空时添加IPv6保留地址(需clientDuid和iaid), 否则添加IPv4保留地址(需address); 启用自动复制(dhcp.failover.autoReplicate)时将IPv4作用域复制到故障转移伙伴服务器")
+	function.SetInputJsonExample(&model.DhcpReservation{
+		ScopeId: "192.168.1.0",
+		IpV4:    "192.168.1.20",
+		Address: "00-1C-23-20-AF-4A",
+		Name:    "printer",
+	})
+	function.SetOutputDataExample(&model.DhcpReservationResult{
+		Replication: &model.DhcpFailoverReplication{
+			Replicated: true,
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) DelReservation(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpReservationDelete{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	if len(argument.IpV6) > 0 {
+		ip := net.ParseIP(argument.IpV6)
+		if ip == nil || ip.To4() != nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("IPv6地址(%s)无效", argument.IpV6))
+			return
+		}
+		err = dhcp.DeleteV6Reservation(argument.IpV6)
+	} else {
+		if net.ParseIP(argument.IpV4).To4() == nil {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("IPv4地址(%s)无效", argument.IpV4))
+			return
+		}
+		err = dhcp.DeleteReservation(argument.IpV4)
+	}
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	result := &model.DhcpReservationResult{}
+	if len(argument.IpV6) < 1 && s.cfg.Dhcp.Failover.AutoReplicate {
+		scope, se := dhcp.GetAddressScope(argument.IpV4)
+		if se != nil {
+			result.Replication = &model.DhcpFailoverReplication{
+				Error: se.Error(),
+			}
+		} else {
+			result.Replication = s.replicate(dhcp, scope.ScopeId)
+		}
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) DelReservationDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "保留地址")
+	function := catalog.AddFunction(method, uri, "删除保留地址")
+	function.SetNote("按IPv4或IPv6地址删除保留地址; 启用自动复制(dhcp.failover.autoReplicate)时将IPv4作用域复制到故障转移伙伴服务器")
+	function.SetInputJsonExample(&model.DhcpReservationDelete{
+		IpV4: "192.168.1.20",
+	})
+	function.SetOutputDataExample(&model.DhcpReservationResult{
+		Replication: &model.DhcpFailoverReplication{
+			Replicated: true,
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) checkScopeId(family, scopeId string) error {
+	ip := net.ParseIP(scopeId)
+	if family == model.DhcpFamilyIpV6 {
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("IPv6作用域前缀(%s)无效", scopeId)
+		}
+	} else if ip.To4() == nil {
+		return fmt.Errorf("作用域ID(%s)无效", scopeId)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"strings"
+	"time"
+)
+
+const (
+	dhcpRogueEventCapacity = 500
+)
+
+func (s *Dhcp) GetRogueReport(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpRogueArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if argument.IdleDays < 0 {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("闲置天数(%d)无效", argument.IdleDays))
+		return
+	}
+
+	result, err := s.getRogueReport(argument.IdleDays)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dhcp) GetRogueReportDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "未知设备")
+	function := catalog.AddFunction(method, uri, "获取未知设备报告")
+	function.SetNote("比较IPv4地址租用与筛选器: 不在允许列表中的租用、在拒绝列表中的租用, 以及闲置的允许列表筛选器; 启用租用历史时根据历史计算闲置天数")
+	function.SetInputJsonExample(&model.DhcpRogueArgument{
+		IdleDays: 30,
+	})
+	function.SetOutputDataExample(&model.DhcpRogueReport{
+		CheckTime: "2020-10-19 08:30:00",
+		IdleDays:  30,
+		History:   true,
+		Unknown: []*model.DhcpLease{
+			{
+				Family:       model.DhcpFamilyIpV4,
+				ScopeId:      "192.168.1.0",
+				IpV4:         "192.168.1.103",
+				Address:      "DA-A1-19-00-11-22",
+				Randomized:   true,
+				HostName:     "phone",
+				AddressState: "Active",
+			},
+		},
+		Denied: []*model.DhcpLease{},
+		Idle: []*model.DhcpRogueFilter{
+			{
+				DhcpFilter: model.DhcpFilter{
+					Allow:   true,
+					Address: "00-1C-23-20-AF-4A",
+					Comment: "描述信息",
+				},
+				LastSeen: "2020-08-01 17:45:00",
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetRogueEvents(ctx gtype.Context, ps gtype.Params) {
+	s.rogueMutex.RLock()
+	results := make([]*model.DhcpRogueEvent, len(s.rogueEvents))
+	copy(results, s.rogueEvents)
+	s.rogueMutex.RUnlock()
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetRogueEventsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "未知设备")
+	function := catalog.AddFunction(method, uri, "获取未知设备事件列表")
+	function.SetNote(fmt.Sprintf("获取定时检查(dhcp.rogue.interval)发现的新未知设备, 最多保留最近%d条, 按时间倒序", dhcpRogueEventCapacity))
+	function.SetOutputDataExample([]*model.DhcpRogueEvent{
+		{
+			Time: "2020-10-19 08:30:00",
+			Kind: model.DhcpRogueKindUnknown,
+			Lease: &model.DhcpLease{
+				Family:       model.DhcpFamilyIpV4,
+				ScopeId:      "192.168.1.0",
+				IpV4:         "192.168.1.103",
+				Address:      "DA-A1-19-00-11-22",
+				Randomized:   true,
+				HostName:     "phone",
+				AddressState: "Active",
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) getRogueReport(idleDays int) (*model.DhcpRogueReport, error) {
+	if idleDays < 1 {
+		idleDays = s.cfg.Dhcp.Rogue.IdleDays
+	}
+
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	list, err := dhcp.GetScopeLeases()
+	if err != nil {
+		return nil, err
+	}
+	leases, scopeErrors := dhcp.FlattenLeases(list)
+	filters, err := dhcp.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSeen map[string]time.Time
+	if s.history != nil {
+		lastSeen = s.history.LastSeen()
+	}
+
+	report := dhcp.GetRogueReport(leases, filters, lastSeen, idleDays, time.Now())
+	report.ScopeErrors = scopeErrors
+
+	return report, nil
+}
+
+func (s *Dhcp) inspect(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.checkRogue()
+	for range ticker.C {
+		s.checkRogue()
+	}
+}
+
+func (s *Dhcp) checkRogue() {
+	report, err := s.getRogueReport(0)
+	if err != nil {
+		s.LogError("dhcp: check unknown devices fail: ", err)
+		return
+	}
+
+	events := make([]*model.DhcpRogueEvent, 0)
+	known := make(map[string]bool)
+	kinds := map[string][]*model.DhcpLease{
+		model.DhcpRogueKindUnknown: report.Unknown,
+		model.DhcpRogueKindDenied:  report.Denied,
+	}
+	for kind, leases := range kinds {
+		for i := 0; i < len(leases); i++ {
+			lease := leases[i]
+			key := fmt.Sprintf("%s|%s", kind, lease.Address)
+			known[key] = true
+			if s.rogueKnown == nil || s.rogueKnown[key] {
+				continue
+			}
+
+			events = append(events, &model.DhcpRogueEvent{
+				Time:  report.CheckTime,
+				Kind:  kind,
+				Lease: lease,
+			})
+			s.LogWarning(fmt.Sprintf("dhcp: %s device found: %s %s %s (%s)",
+				kind, lease.Address, lease.IpV4, lease.HostName, lease.Vendor))
+		}
+	}
+	if len(report.ScopeErrors) > 0 {
+		// keep the previous devices known while some scopes failed, so the
+		// devices of those scopes are not reported again on the next check
+		for key := range s.rogueKnown {
+			known[key] = true
+		}
+		s.LogWarning(fmt.Sprintf("dhcp: check unknown devices incomplete: %s", strings.Join(report.ScopeErrors, "; ")))
+	}
+	if s.rogueKnown == nil {
+		s.LogInfo(fmt.Sprintf("dhcp: unknown devices baseline: %d unknown, %d denied",
+			len(report.Unknown), len(report.Denied)))
+	}
+	s.rogueKnown = known
+
+	if len(events) < 1 {
+		return
+	}
+	s.rogueMutex.Lock()
+	defer s.rogueMutex.Unlock()
+	for i := 0; i < len(events); i++ {
+		s.rogueEvents = append([]*model.DhcpRogueEvent{events[i]}, s.rogueEvents...)
+	}
+	if len(s.rogueEvents) > dhcpRogueEventCapacity {
+		s.rogueEvents = s.rogueEvents[:dhcpRogueEventCapacity]
+	}
+}
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"net"
+)
+
+func (s *Dhcp) GetScopes(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpFamilyArgument{}
+	ctx.GetJson(argument)
+	err := s.checkFamily(argument.Family)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	results, err := s.getScopes(dhcp, argument.Family)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetScopesDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "获取作用域列表")
+	function.SetNote("获取IPv4和IPv6作用域列表, 可按地址族筛选")
+	function.SetInputJsonExample(&model.DhcpFamilyArgument{
+		Family: model.DhcpFamilyIpV4,
+	})
+	function.SetOutputDataExample([]*model.DhcpScope{
+		{
+			Family:     model.DhcpFamilyIpV4,
+			ScopeId:    "192.168.1.0",
+			Name:       "office",
+			SubnetMask: "255.255.255.0",
+			StartRange: "192.168.1.10",
+			EndRange:   "192.168.1.250",
+			State:      "Active",
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetExclusions(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpScopeArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.ScopeId) < 1 {
+		ctx.Error(gtype.ErrInput, "作用域ID(scopeId)为空")
+		return
+	}
+	if net.ParseIP(argument.ScopeId).To4() == nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("作用域ID(%s)无效", argument.ScopeId))
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetExclusions(argument.ScopeId)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetExclusionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "获取排除范围列表")
+	function.SetNote("获取指定IPv4作用域的排除范围列表")
+	function.SetInputJsonExample(&model.DhcpScopeArgument{
+		ScopeId: "192.168.1.0",
+	})
+	function.SetOutputDataExample([]*model.DhcpExclusion{
+		{
+			ScopeId:    "192.168.1.0",
+			StartRange: "192.168.1.10",
+			EndRange:   "192.168.1.19",
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) AddExclusion(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpExclusion{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkExclusionArgument(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	err = dhcp.CheckExclusion(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = dhcp.AddExclusion(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(nil)
+}
+
+func (s *Dhcp) AddExclusionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "添加排除范围")
+	function.SetNote("添加IPv4排除范围, 范围必须在作用域地址范围内, 且不能包含保留地址或与已有排除范围重叠")
+	function.SetInputJsonExample(&model.DhcpExclusion{
+		ScopeId:    "192.168.1.0",
+		StartRange: "192.168.1.10",
+		EndRange:   "192.168.1.19",
+	})
+	function.SetOutputDataExample(nil)
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) DelExclusion(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DhcpExclusion{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	err = s.checkExclusionArgument(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	dhcp := &assist.Dhcp{}
+	err = dhcp.DeleteExclusion(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(nil)
+}
+
+func (s *Dhcp) DelExclusionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "删除排除范围")
+	function.SetNote("删除IPv4作用域中已存在的排除范围")
+	function.SetInputJsonExample(&model.DhcpExclusion{
+		ScopeId:    "192.168.1.0",
+		StartRange: "192.168.1.10",
+		EndRange:   "192.168.1.19",
+	})
+	function.SetOutputDataExample(nil)
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) checkExclusionArgument(argument *model.DhcpExclusion) error {
+	if len(argument.ScopeId) < 1 {
+		return fmt.Errorf("作用域ID(scopeId)为空")
+	}
+	if net.ParseIP(argument.ScopeId).To4() == nil {
+		return fmt.Errorf("作用域ID(%s)无效", argument.ScopeId)
+	}
+	if net.ParseIP(argument.StartRange).To4() == nil {
+		return fmt.Errorf("起始地址(%s)无效", argument.StartRange)
+	}
+	if net.ParseIP(argument.EndRange).To4() == nil {
+		return fmt.Errorf("结束地址(%s)无效", argument.EndRange)
+	}
+
+	return nil
+}
+
+func (s *Dhcp) getScopes(dhcp *assist.Dhcp, family string) ([]*model.DhcpScope, error) {
+	results := make([]*model.DhcpScope, 0)
+	if family != model.DhcpFamilyIpV6 {
+		scopes, err := dhcp.GetScopes()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, scopes...)
+	}
+	if family != model.DhcpFamilyIpV4 {
+		scopes, err := dhcp.GetV6Scopes()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, scopes...)
+	}
+
+	return results, nil
+}
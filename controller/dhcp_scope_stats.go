@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"time"
+)
+
+func (s *Dhcp) GetScopeStatistics(ctx gtype.Context, ps gtype.Params) {
+	dhcp := &assist.Dhcp{}
+	results, err := dhcp.GetScopeStatistics()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.setAlarmLevels(results)
+
+	ctx.Success(results)
+}
+
+func (s *Dhcp) GetScopeStatisticsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "获取作用域使用统计")
+	function.SetNote("获取IPv4作用域地址使用统计, level根据配置的阈值计算")
+	function.SetOutputDataExample([]*model.DhcpScopeStatistics{
+		{
+			ScopeId:    "192.168.1.0",
+			Total:      241,
+			InUse:      200,
+			Free:       41,
+			Reserved:   12,
+			Pending:    0,
+			Percentage: 82.99,
+			Level:      model.DhcpAlarmLevelWarning,
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) GetStatus(ctx gtype.Context, ps gtype.Params) {
+	s.statusMutex.RLock()
+	status := s.status
+	s.statusMutex.RUnlock()
+
+	if status.Alarms == nil {
+		status.Alarms = make([]*model.DhcpScopeStatistics, 0)
+	}
+
+	ctx.Success(status)
+}
+
+func (s *Dhcp) GetStatusDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "作用域")
+	function := catalog.AddFunction(method, uri, "获取服务状态")
+	function.SetNote("获取最近一次定时检查的结果, 包括使用率超过阈值的作用域")
+	function.SetOutputDataExample(&model.DhcpStatus{
+		CheckTime: "2020-10-19 08:30:00",
+		Error:     "",
+		Alarms: []*model.DhcpScopeStatistics{
+			{
+				ScopeId:    "192.168.1.0",
+				Total:      241,
+				InUse:      236,
+				Free:       5,
+				Reserved:   12,
+				Percentage: 97.93,
+				Level:      model.DhcpAlarmLevelCritical,
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dhcp) checkScopeStatistics() {
+	status := model.DhcpStatus{
+		CheckTime: time.Now().Format("2006-01-02 15:04:05"),
+		Alarms:    make([]*model.DhcpScopeStatistics, 0),
+	}
+
+	dhcp := &assist.Dhcp{}
+	items, err := dhcp.GetScopeStatistics()
+	if err != nil {
+		status.Error = err.Error()
+		s.LogError("dhcp: get scope statistics fail: ", err)
+	} else {
+		s.setAlarmLevels(items)
+
+		levels := make(map[string]string)
+		for i := 0; i < len(items); i++ {
+			item := items[i]
+			levels[item.ScopeId] = item.Level
+			if item.Level != model.DhcpAlarmLevelNone {
+				status.Alarms = append(status.Alarms, item)
+			}
+			if item.Level == s.alarmLevels[item.ScopeId] {
+				continue
+			}
+
+			if item.Level == model.DhcpAlarmLevelNone {
+				s.LogInfo(fmt.Sprintf("dhcp: scope %s utilization back to normal: %.2f%% (%d/%d)",
+					item.ScopeId, item.Percentage, item.InUse, item.Total))
+			} else {
+				s.LogWarning(fmt.Sprintf("dhcp: scope %s utilization %s: %.2f%% (%d/%d), %d free",
+					item.ScopeId, item.Level, item.Percentage, item.InUse, item.Total, item.Free))
+			}
+		}
+		s.alarmLevels = levels
+	}
+
+	s.statusMutex.Lock()
+	s.status = status
+	s.statusMutex.Unlock()
+}
+
+func (s *Dhcp) setAlarmLevels(items []*model.DhcpScopeStatistics) {
+	alarm := s.cfg.Dhcp.Alarm
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		warning := alarm.Warning
+		critical := alarm.Critical
+		for j := 0; j < len(alarm.Scopes); j++ {
+			scope := alarm.Scopes[j]
+			if scope.ScopeId == item.ScopeId {
+				warning = scope.Warning
+				critical = scope.Critical
+				break
+			}
+		}
+
+		item.Level = model.DhcpAlarmLevelNone
+		if critical > 0 && item.Percentage >= critical {
+			item.Level = model.DhcpAlarmLevelCritical
+		} else if warning > 0 && item.Percentage >= warning {
+			item.Level = model.DhcpAlarmLevelWarning
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"time"
+)
+
+func (s *Dns) GetReconcileReport(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DnsReconcileArgument{}
+	ctx.GetJson(argument)
+
+	zones, err := s.getReconcileZones(argument.ZoneName)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	report, err := s.getReconcileReport(zones)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(report)
+}
+
+func (s *Dns) GetReconcileReportDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "租用核对")
+	function := catalog.AddFunction(method, uri, "获取租用与记录核对报告")
+	function.SetNote("将IPv4有效租用与配置域名的A记录进行核对: 记录指向主机已不再租用的地址(stale), 租用没有对应记录(missing), 记录位于作用域范围内但没有有效租用(orphan)")
+	function.SetInputJsonExample(&model.DnsReconcileArgument{
+		ZoneName: "example.com",
+	})
+	function.SetOutputDataExample(&model.DnsReconcileReport{
+		CheckTime: "2020-10-19 08:30:00",
+		ZoneNames: []string{"example.com"},
+		Stale: []*model.DnsReconcileItem{
+			{
+				Id:       "6f1c2b9a4e0d7c83b5a1f2e9d4c07a61",
+				Kind:     model.DnsReconcileKindStale,
+				Action:   model.DnsReconcileActionUpdate,
+				ZoneName: "example.com",
+				Name:     "pc-01",
+				Data:     "192.168.1.50",
+				IpV4:     "192.168.1.103",
+				Address:  "00-1C-23-20-AF-4A",
+				HostName: "pc-01.example.com",
+			},
+		},
+		Missing: []*model.DnsReconcileItem{
+			{
+				Id:       "a9e3d05b7c6f41e28d1b3c5f7a9e0d24",
+				Kind:     model.DnsReconcileKindMissing,
+				Action:   model.DnsReconcileActionAdd,
+				ZoneName: "example.com",
+				Name:     "pc-02",
+				IpV4:     "192.168.1.104",
+				Address:  "00-1C-23-20-AF-4B",
+				HostName: "pc-02.example.com",
+			},
+		},
+		Orphan: []*model.DnsReconcileItem{
+			{
+				Id:       "3b7d9f1e5a2c4608e1f3b5d7c9a0e246",
+				Kind:     model.DnsReconcileKindOrphan,
+				Action:   model.DnsReconcileActionDelete,
+				ZoneName: "example.com",
+				Name:     "pc-03",
+				Data:     "192.168.1.105",
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dns) FixReconcile(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.DnsReconcileFix{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Ids) < 1 {
+		ctx.Error(gtype.ErrInput, "项ID(ids)为空")
+		return
+	}
+	zones, err := s.getReconcileZones(argument.ZoneName)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	// the items are taken from a fresh report, not from the client
+	report, err := s.getReconcileReport(zones)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	dns := &assist.Dns{}
+	items, missing := dns.SelectReconcile(report, argument.Ids)
+	result := dns.FixReconcile(items)
+	for i := 0; i < len(missing); i++ {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: not found in current report", missing[i]))
+	}
+
+	ctx.Success(result)
+}
+
+func (s *Dns) FixReconcileDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "租用核对")
+	function := catalog.AddFunction(method, uri, "执行核对建议")
+	function.SetNote("重新生成核对报告, 按选择的项ID修改A记录: add-添加记录指向租用地址; delete-删除记录; update-添加租用地址记录后删除原记录; 同一主机的多条过期记录只添加一次租用地址记录; 已不在当前报告中的项不执行并在errors中返回")
+	function.SetInputJsonExample(&model.DnsReconcileFix{
+		DnsReconcileArgument: model.DnsReconcileArgument{
+			ZoneName: "example.com",
+		},
+		Ids: []string{"6f1c2b9a4e0d7c83b5a1f2e9d4c07a61"},
+	})
+	function.SetOutputDataExample(&model.DnsReconcileFixResult{
+		Applied: []*model.DnsReconcileItem{
+			{
+				Id:       "6f1c2b9a4e0d7c83b5a1f2e9d4c07a61",
+				Kind:     model.DnsReconcileKindStale,
+				Action:   model.DnsReconcileActionUpdate,
+				ZoneName: "example.com",
+				Name:     "pc-01",
+				Data:     "192.168.1.50",
+				IpV4:     "192.168.1.103",
+			},
+		},
+		Errors: []string{},
+	})
+	function.AddOutputError(gtype.ErrInput)
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Dns) getReconcileZones(zoneName string) ([]string, error) {
+	zones := s.cfg.Dns.ZoomNames
+	if len(zoneName) > 0 {
+		if !s.isZoneConfigured(zoneName) {
+			return nil, fmt.Errorf("域名(%s)未配置", zoneName)
+		}
+		zones = []string{zoneName}
+	}
+	if len(zones) < 1 {
+		return nil, fmt.Errorf("未配置域名(dns.zoomNames)")
+	}
+
+	return zones, nil
+}
+
+func (s *Dns) getReconcileReport(zones []string) (*model.DnsReconcileReport, error) {
+	records := make(map[string][]*model.DnsRecord)
+	for i := 0; i < len(zones); i++ {
+		dns := &assist.Dns{
+			ZoneName: zones[i],
+		}
+		items, err := dns.GetRecords()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", zones[i], err)
+		}
+		records[zones[i]] = items
+	}
+
+	dhcp := &assist.Dhcp{Concurrency: s.cfg.Dhcp.Concurrency}
+	scopes, err := dhcp.GetScopes()
+	if err != nil {
+		return nil, err
+	}
+	list, err := dhcp.GetScopeLeases()
+	if err != nil {
+		return nil, err
+	}
+	leases, scopeErrors := dhcp.FlattenLeases(list)
+
+	// records in the range of a failed scope would all look orphaned
+	failed := dhcp.FailedScopes(list)
+	checked := make([]*model.DhcpScope, 0)
+	for i := 0; i < len(scopes); i++ {
+		if !failed[scopes[i].ScopeId] {
+			checked = append(checked, scopes[i])
+		}
+	}
+
+	dns := &assist.Dns{}
+	report := dns.Reconcile(zones, records, leases, checked, time.Now())
+	report.ScopeErrors = scopeErrors
+
+	return report, nil
+}
+
+func (s *Dns) isZoneConfigured(zoneName string) bool {
+	zones := s.cfg.Dns.ZoomNames
+	for i := 0; i < len(zones); i++ {
+		if zones[i] == zoneName {
+			return true
+		}
+	}
+
+	return false
+}
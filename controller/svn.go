@@ -7,6 +7,7 @@ import (
 	"github.com/csby/gwin/model"
 	"github.com/csby/gwsf/gtype"
 	"strings"
+	"sync"
 )
 
 func NewSvn(log gtype.Log, cfg *config.Config) *Svn {
@@ -19,17 +20,15 @@ func NewSvn(log gtype.Log, cfg *config.Config) *Svn {
 
 type Svn struct {
 	base
+
+	tokenMutex   sync.Mutex
+	deleteTokens map[string]*svnDeleteToken
+	jobMutex     sync.RWMutex
+	jobs         []*model.SvnJob
 }
 
 func (s *Svn) GetUsers(ctx gtype.Context, ps gtype.Params) {
-	ad := &assist.MsAd{}
-	if s.cfg != nil {
-		ad.Host = s.cfg.Svn.Ad.Host
-		ad.Port = s.cfg.Svn.Ad.Port
-		ad.Base = s.cfg.Svn.Ad.Base
-		ad.Account = s.cfg.Svn.Ad.Account
-		ad.Password = s.cfg.Svn.Ad.Password
-	}
+	ad := s.newAd()
 	results, err := ad.GetAllUsers()
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
@@ -52,8 +51,35 @@ func (s *Svn) GetUsersDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	function.AddOutputError(gtype.ErrInput)
 }
 
+func (s *Svn) GetGroups(ctx gtype.Context, ps gtype.Params) {
+	ad := s.newAd()
+	results, err := ad.GetAllGroups()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Svn) GetGroupsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "获取组列表")
+	function.SetNote("获取AD中的组, 组ID可用于添加、修改及删除访问权限")
+	function.SetOutputDataExample([]*model.MsAdGroup{
+		{
+			Id:          "S-1-5-21-1114322273-403004966-1807125474-1105",
+			Name:        "Developers",
+			Account:     "Developers",
+			Description: "",
+			Members:     30,
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
 func (s *Svn) GetRepositories(ctx gtype.Context, ps gtype.Params) {
-	svn := &assist.Svn{}
+	svn := s.newSvn()
 	results, err := svn.GetRepositories(false)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
@@ -95,7 +121,7 @@ func (s *Svn) GetFolders(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
+	svn := s.newSvn()
 	results, err := svn.GetRepositoryFolders(argument.Name, argument.Path, false)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
@@ -146,7 +172,13 @@ func (s *Svn) NewRepository(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
+	template, err := s.getTemplate(argument.Template)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	svn := s.newSvn()
 
 	rs, re := svn.GetRepositories(false)
 	if re != nil {
@@ -160,11 +192,17 @@ func (s *Svn) NewRepository(ctx gtype.Context, ps gtype.Params) {
 		}
 	}
 
-	err = svn.NewRepository(argument.Name)
+	err = svn.NewRepository(argument.Name, template.Folders)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
 	}
+	err = s.applyTemplate(svn, argument.Name, template, s.getOperator(ctx))
+	if err != nil {
+		s.LogError(fmt.Sprintf("svn: repository '%s' created, apply template '%s' fail: %v", argument.Name, template.Name, err))
+		ctx.Error(gtype.ErrInternal.SetDetail(fmt.Errorf("repository '%s' created, but apply template '%s' fail: %v", argument.Name, template.Name, err)))
+		return
+	}
 
 	ctx.Success(argument.Name)
 }
@@ -172,9 +210,10 @@ func (s *Svn) NewRepository(ctx gtype.Context, ps gtype.Params) {
 func (s *Svn) NewRepositoryDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	catalog := s.createCatalog(doc, "存储库")
 	function := catalog.AddFunction(method, uri, "新建存储库")
-	function.SetNote("成功时返回存储库名称, 并创建3个文件夹: branches,tags,trunk")
+	function.SetNote("成功时返回存储库名称, 并按模板(svn.templates)创建文件夹、添加访问权限、安装钩子及初始提交; 未指定模板时使用standard模板, 创建3个文件夹: branches,tags,trunk")
 	function.SetInputJsonExample(&model.SvnRepositoryNew{
-		Name: "MyRepo",
+		Name:     "MyRepo",
+		Template: svnTemplateStandard,
 	})
 	function.SetOutputDataExample("MyRepo")
 	function.AddOutputError(gtype.ErrInternal)
@@ -197,12 +236,15 @@ func (s *Svn) GetPermissions(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
+	svn := s.newSvn()
 	results, err := svn.GetPermissions(argument.Name, argument.Path)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
 	}
+	s.markGroups(svn, len(results), func(i int) (string, *bool) {
+		return results[i].AccountId, &results[i].Group
+	})
 
 	ctx.Success(results)
 }
@@ -220,6 +262,7 @@ func (s *Svn) GetPermissionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 			AccountName: "",
 			AccessLevel: model.SvnPermissionReadWrite,
 			Inherited:   true,
+			Group:       true,
 		},
 	})
 	function.AddOutputError(gtype.ErrInternal)
@@ -238,7 +281,7 @@ func (s *Svn) GetUserPermissions(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
+	svn := s.newSvn()
 	results, err := svn.GetUserPermissions(argument.AccountId)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
@@ -285,8 +328,8 @@ func (s *Svn) AddPermission(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
-	err = svn.AddPermission(argument.Repository, argument.Path, argument.AccountId, argument.AccessLevel)
+	svn := s.newSvn()
+	err = svn.AddPermission(argument.Repository, argument.Path, s.accountId(svn, &argument.SvnPermissionArgument), argument.AccessLevel)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
@@ -323,8 +366,8 @@ func (s *Svn) ModPermission(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
-	err = svn.SetPermission(argument.Repository, argument.Path, argument.AccountId, argument.AccessLevel)
+	svn := s.newSvn()
+	err = svn.SetPermission(argument.Repository, argument.Path, s.accountId(svn, &argument.SvnPermissionArgument), argument.AccessLevel)
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
@@ -361,8 +404,8 @@ func (s *Svn) DelPermission(ctx gtype.Context, ps gtype.Params) {
 		return
 	}
 
-	svn := &assist.Svn{}
-	err = svn.RemovePermission(argument.Repository, argument.Path, argument.AccountId)
+	svn := s.newSvn()
+	err = svn.RemovePermission(argument.Repository, argument.Path, s.accountId(svn, argument))
 	if err != nil {
 		ctx.Error(gtype.ErrInternal.SetDetail(err))
 		return
@@ -379,6 +422,72 @@ func (s *Svn) DelPermissionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
 	function.AddOutputError(gtype.ErrInput)
 }
 
+func (s *Svn) newAd() *assist.MsAd {
+	ad := &assist.MsAd{}
+	if s.cfg != nil {
+		ad.Host = s.cfg.Svn.Ad.Host
+		ad.Port = s.cfg.Svn.Ad.Port
+		ad.Base = s.cfg.Svn.Ad.Base
+		ad.Account = s.cfg.Svn.Ad.Account
+		ad.Password = s.cfg.Svn.Ad.Password
+	}
+
+	return ad
+}
+
+// accountId returns the account of the access rule, groups of the authz file are prefixed by @
+func (s *Svn) accountId(svn assist.SvnBackend, argument *model.SvnPermissionArgument) string {
+	if !argument.Group {
+		return argument.AccountId
+	}
+	if _, ok := svn.(*assist.SvnAuthz); ok && !strings.HasPrefix(argument.AccountId, "@") {
+		return "@" + argument.AccountId
+	}
+
+	return argument.AccountId
+}
+
+// markGroups marks the accounts of AD and well-known groups for VisualSVN Server, account returns the id and group flag of item i,
+// rules of the authz file are marked by the backend itself.
+func (s *Svn) markGroups(svn assist.SvnBackend, count int, account func(i int) (string, *bool)) {
+	if _, ok := svn.(*assist.Svn); !ok || count < 1 {
+		return
+	}
+
+	ad := s.newAd()
+	sids := make([]string, 0)
+	exists := make(map[string]bool)
+	for i := 0; i < count; i++ {
+		accountId, _ := account(i)
+		if exists[accountId] || !strings.HasPrefix(accountId, "S-1-") || ad.IsWellKnownGroup(accountId) {
+			continue
+		}
+		exists[accountId] = true
+		sids = append(sids, accountId)
+	}
+	groups, err := ad.GetGroupIds(sids)
+	if err != nil {
+		s.LogWarning(fmt.Sprintf("svn: get ad groups fail: %v", err))
+	}
+
+	for i := 0; i < count; i++ {
+		accountId, group := account(i)
+		*group = groups[accountId] || ad.IsWellKnownGroup(accountId)
+	}
+}
+
+func (s *Svn) newSvn() assist.SvnBackend {
+	if s.cfg != nil && s.cfg.Svn.Backend == config.SvnBackendAuthz {
+		return &assist.SvnAuthz{
+			Root:      s.cfg.Svn.Authz.Root,
+			AuthzFile: s.cfg.Svn.Authz.File,
+			Url:       s.cfg.Svn.Authz.Url,
+		}
+	}
+
+	return &assist.Svn{}
+}
+
 func (s *Svn) createCatalog(doc gtype.Doc, names ...string) gtype.Catalog {
 	root := s.createRootCatalog(doc, "SVN")
 	count := len(names)
@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"strings"
+	"time"
+)
+
+const (
+	svnJobCapacity = 100
+)
+
+func (s *Svn) NewBackup(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnBackupNew{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+	if len(argument.Kind) < 1 {
+		argument.Kind = model.SvnBackupKindFull
+	}
+	if argument.Kind != model.SvnBackupKindFull &&
+		argument.Kind != model.SvnBackupKindIncremental &&
+		argument.Kind != model.SvnBackupKindHotcopy {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("备份类型(%s)无效", argument.Kind))
+		return
+	}
+
+	folder, err := s.newSvn().GetRepositoryPath(argument.Repository)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	job := s.newJob(model.SvnJobKindBackup, argument.Repository, nil)
+	if job == nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.Repository))
+		return
+	}
+	go func(job *model.SvnJob, repository, folder, kind string) {
+		backup := &assist.SvnBackup{Folder: s.cfg.Svn.Backup.Folder}
+		result, be := backup.Backup(repository, folder, kind, time.Now(), func(revision, total int) {
+			s.setJobProgress(job, revision, total)
+		})
+		s.endJob(job, result, be)
+	}(job, argument.Repository, folder, argument.Kind)
+
+	ctx.Success(s.copyJob(job))
+}
+
+func (s *Svn) NewBackupDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "备份")
+	function := catalog.AddFunction(method, uri, "备份存储库")
+	function.SetNote("在后台使用svnadmin dump或hotcopy备份存储库到配置的目录(svn.backup.folder), 文件使用gzip压缩并记录SHA256校验和; 立即返回任务信息, 通过任务列表查询进度")
+	function.SetInputJsonExample(&model.SvnBackupNew{
+		Repository: "MyRepo",
+		Kind:       model.SvnBackupKindFull,
+	})
+	function.SetOutputDataExample(s.jobExample(model.SvnJobKindBackup))
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) GetBackups(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnBackupArgument{}
+	ctx.GetJson(argument)
+
+	backup := &assist.SvnBackup{Folder: s.cfg.Svn.Backup.Folder}
+	results, err := backup.GetBackups(argument.Repository)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Svn) GetBackupsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "备份")
+	function := catalog.AddFunction(method, uri, "获取备份列表")
+	function.SetInputJsonExample(&model.SvnBackupArgument{
+		Repository: "MyRepo",
+	})
+	function.SetOutputDataExample([]*model.SvnBackup{
+		s.backupExample(),
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Svn) RestoreBackup(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnBackupRestore{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Id) < 1 {
+		ctx.Error(gtype.ErrInput, "备份ID(id)为空")
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "新存储库名称(name)为空")
+		return
+	}
+
+	backup := &assist.SvnBackup{Folder: s.cfg.Svn.Backup.Folder}
+	item, err := backup.GetBackup(argument.Id)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	svn := s.newSvn()
+	rs, err := svn.GetRepositories(false)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	for i := 0; i < len(rs); i++ {
+		if strings.ToLower(argument.Name) == strings.ToLower(rs[i].Repository) {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库名称(%s)已存在", argument.Name))
+			return
+		}
+	}
+	folder, err := svn.GetRepositoryPath(argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	job := s.newJob(model.SvnJobKindRestore, argument.Name, item)
+	if job == nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.Name))
+		return
+	}
+	go func(job *model.SvnJob, id, folder string) {
+		re := backup.Restore(id, folder, func(revision, total int) {
+			s.setJobProgress(job, revision, total)
+		})
+		s.endJob(job, nil, re)
+	}(job, argument.Id, folder)
+
+	ctx.Success(s.copyJob(job))
+}
+
+func (s *Svn) RestoreBackupDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "备份")
+	function := catalog.AddFunction(method, uri, "还原备份")
+	function.SetNote("在后台校验备份文件后还原到新的存储库, 增量备份时自动依次加载之前的完整及增量备份; 立即返回任务信息, 通过任务列表查询进度")
+	function.SetInputJsonExample(&model.SvnBackupRestore{
+		Id:   "MyRepo-full-20201019083000",
+		Name: "MyRepoRestored",
+	})
+	function.SetOutputDataExample(s.jobExample(model.SvnJobKindRestore))
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) GetJobs(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnJobArgument{}
+	ctx.GetJson(argument)
+
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	results := make([]*model.SvnJob, 0)
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		job := s.jobs[i]
+		if len(argument.Id) > 0 && job.Id != argument.Id {
+			continue
+		}
+		item := *job
+		results = append(results, &item)
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Svn) GetJobsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "备份")
+	function := catalog.AddFunction(method, uri, "获取任务列表")
+	function.SetNote("获取备份及还原任务, 最新的在前, 最多保留100个")
+	function.SetInputJsonExample(&model.SvnJobArgument{
+		Id: "",
+	})
+	function.SetOutputDataExample([]*model.SvnJob{
+		s.jobExample(model.SvnJobKindBackup),
+	})
+}
+
+// newJob registers a running job, or returns nil when the repository already
+// has one; the check and the registration share the lock.
+func (s *Svn) newJob(kind, repository string, backup *model.SvnBackup) *model.SvnJob {
+	job := &model.SvnJob{
+		Id:         gtype.NewGuid(),
+		Kind:       kind,
+		Repository: repository,
+		Status:     model.SvnJobStatusRunning,
+		StartTime:  time.Now().Format("2006-01-02 15:04:05"),
+		Backup:     backup,
+	}
+
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	if s.findRunningJob(repository) != nil {
+		return nil
+	}
+	s.jobs = append(s.jobs, job)
+	// the oldest finished jobs are dropped, running ones still block their repository
+	for i := 0; len(s.jobs) > svnJobCapacity && i < len(s.jobs); {
+		if s.jobs[i].Status == model.SvnJobStatusRunning {
+			i++
+			continue
+		}
+		s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+	}
+
+	return job
+}
+
+func (s *Svn) copyJob(job *model.SvnJob) *model.SvnJob {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	item := *job
+	return &item
+}
+
+func (s *Svn) setJobProgress(job *model.SvnJob, revision, total int) {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job.Revision = revision
+	job.Total = total
+	if total > 0 {
+		job.Progress = float64(int(float64(revision)*10000/float64(total)+0.5)) / 100
+	}
+}
+
+func (s *Svn) endJob(job *model.SvnJob, backup *model.SvnBackup, err error) {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job.EndTime = time.Now().Format("2006-01-02 15:04:05")
+	if err != nil {
+		job.Status = model.SvnJobStatusFailed
+		job.Error = err.Error()
+		s.LogError(fmt.Sprintf("svn: %s '%s' fail: %v", job.Kind, job.Repository, err))
+		return
+	}
+
+	job.Status = model.SvnJobStatusSucceeded
+	job.Progress = 100
+	if backup != nil {
+		job.Backup = backup
+	}
+	s.LogInfo(fmt.Sprintf("svn: %s '%s' succeeded", job.Kind, job.Repository))
+}
+
+func (s *Svn) isJobRunning(repository string) bool {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	return s.findRunningJob(repository) != nil
+}
+
+// findRunningJob must be called with jobMutex held.
+func (s *Svn) findRunningJob(repository string) *model.SvnJob {
+	for i := 0; i < len(s.jobs); i++ {
+		job := s.jobs[i]
+		if job.Status == model.SvnJobStatusRunning && strings.EqualFold(job.Repository, repository) {
+			return job
+		}
+	}
+
+	return nil
+}
+
+func (s *Svn) backupExample() *model.SvnBackup {
+	return &model.SvnBackup{
+		Id:           "MyRepo-full-20201019083000",
+		Repository:   "MyRepo",
+		Kind:         model.SvnBackupKindFull,
+		FromRevision: 0,
+		ToRevision:   128,
+		File:         "MyRepo-full-20201019083000.dump.gz",
+		Size:         1048576,
+		Sha256:       "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		Time:         "2020-10-19 08:30:00",
+	}
+}
+
+func (s *Svn) jobExample(kind string) *model.SvnJob {
+	return &model.SvnJob{
+		Id:         gtype.NewGuid(),
+		Kind:       kind,
+		Repository: "MyRepo",
+		Status:     model.SvnJobStatusRunning,
+		Revision:   64,
+		Total:      129,
+		Progress:   49.61,
+		StartTime:  "2020-10-19 08:30:00",
+		Backup:     s.backupExample(),
+	}
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+)
+
+func (s *Svn) GetEffectivePermission(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnEffectiveArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+	if len(argument.Path) < 1 {
+		ctx.Error(gtype.ErrInput, "路径(path)为空")
+		return
+	}
+	if len(argument.AccountId) < 1 {
+		ctx.Error(gtype.ErrInput, "账号ID(accountId)为空")
+		return
+	}
+
+	svn := s.newSvn()
+	groups, err := s.getAccountGroups(svn, argument.AccountId)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	rules, err := svn.GetAccessRules(argument.Repository)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.markGroups(svn, len(rules), func(i int) (string, *bool) {
+		return rules[i].AccountId, &rules[i].Group
+	})
+
+	_, authz := svn.(*assist.SvnAuthz)
+	effective := &assist.SvnEffective{AccountFirst: !authz}
+	ctx.Success(effective.Calculate(argument.Repository, argument.Path, argument.AccountId, groups, rules))
+}
+
+func (s *Svn) GetEffectivePermissionDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "获取有效访问权限")
+	function.SetNote("计算用户在存储库路径上的有效访问权限: 从该路径向上直到根路径, 同一路径先存储库规则后所有存储库(*)规则, " +
+		"第一个有匹配规则(包括用户所属的组, VisualSVN时展开AD嵌套组)的路径决定结果; " +
+		"VisualSVN时用户自身的规则优先于组规则, authz时取最宽松的规则; 返回决定结果的规则及说明")
+	function.SetInputJsonExample(&model.SvnEffectiveArgument{
+		Repository: "MyRepo",
+		Path:       "/trunk/src",
+		AccountId:  "S-1-5-21-1114322273-403004966-1807125474-1104",
+	})
+	rule := &model.SvnAccessRule{
+		Repository:  "MyRepo",
+		Path:        "/trunk",
+		AccountId:   "S-1-5-21-1114322273-403004966-1807125474-1105",
+		AccountName: "EXAMPLE\\Developers",
+		AccessLevel: model.SvnPermissionReadWrite,
+		Group:       true,
+	}
+	function.SetOutputDataExample(&model.SvnEffectivePermission{
+		Repository:  "MyRepo",
+		Path:        "/trunk/src",
+		AccountId:   "S-1-5-21-1114322273-403004966-1807125474-1104",
+		AccessLevel: model.SvnPermissionReadWrite,
+		Groups:      []string{"S-1-5-21-1114322273-403004966-1807125474-1105", "S-1-5-21-1114322273-403004966-1807125474-513"},
+		Rule:        rule,
+		Matches:     []*model.SvnAccessRule{rule},
+		Reason:      "group 'EXAMPLE\\Developers' has read-write on [MyRepo:/trunk], inherited as the nearest rule",
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+// getAccountGroups returns the groups of the account, from the authz file or AD with well-known groups
+func (s *Svn) getAccountGroups(svn assist.SvnBackend, accountId string) ([]string, error) {
+	if authz, ok := svn.(*assist.SvnAuthz); ok {
+		return authz.GetAccountGroups(accountId)
+	}
+
+	ad := s.newAd()
+	items, err := ad.GetAccountGroups(accountId)
+	if err != nil {
+		return nil, fmt.Errorf("get groups of '%s' from ad fail: %v", accountId, err)
+	}
+	groups := make([]string, 0, len(items)+2)
+	for i := 0; i < len(items); i++ {
+		groups = append(groups, items[i].Id)
+	}
+
+	// Everyone and Authenticated Users
+	return append(groups, "S-1-1-0", "S-1-5-11"), nil
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+)
+
+func (s *Svn) NewFolder(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnFolderNew{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+	admin := &assist.SvnAdmin{}
+	itemPath, err := admin.ItemPath(argument.Path)
+	if err != nil || itemPath == "/" {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("文件夹路径(%s)无效", argument.Path))
+		return
+	}
+	if len(argument.Message) < 1 {
+		argument.Message = fmt.Sprintf("Create folder %s", itemPath)
+	}
+
+	folder, err := s.newSvn().GetRepositoryPath(argument.Repository)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	author := s.getOperator(ctx)
+	revision, err := admin.NewFolder(folder, itemPath, argument.Message, author)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.LogInfo(fmt.Sprintf("svn: folder '%s%s' created in r%d by %s", argument.Repository, itemPath, revision, author))
+
+	ctx.Success(&model.SvnCommit{
+		Repository: argument.Repository,
+		Path:       itemPath,
+		Revision:   revision,
+		Author:     author,
+		Message:    argument.Message,
+	})
+}
+
+func (s *Svn) NewFolderDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "文件夹")
+	function := catalog.AddFunction(method, uri, "新建文件夹")
+	function.SetNote("在存储库中创建文件夹(包括不存在的上级文件夹), 提交者为登录账号, 未登录时为客户端地址; 路径已存在时失败")
+	function.SetInputJsonExample(&model.SvnFolderNew{
+		Repository: "MyRepo",
+		Path:       "/trunk/docs",
+		Message:    "Create folder /trunk/docs",
+	})
+	function.SetOutputDataExample(&model.SvnCommit{
+		Repository: "MyRepo",
+		Path:       "/trunk/docs",
+		Revision:   129,
+		Author:     "admin",
+		Message:    "Create folder /trunk/docs",
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) NewBranch(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnBranchNew{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "名称(name)为空")
+		return
+	}
+	if argument.Revision < 0 {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("修订版本号(%d)无效", argument.Revision))
+		return
+	}
+	parent := "/branches"
+	if len(argument.Kind) < 1 || argument.Kind == model.SvnBranchKindBranch {
+		argument.Kind = model.SvnBranchKindBranch
+	} else if argument.Kind == model.SvnBranchKindTag {
+		parent = "/tags"
+	} else {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("类型(%s)无效", argument.Kind))
+		return
+	}
+	if len(argument.Source) < 1 {
+		argument.Source = "/trunk"
+	}
+
+	admin := &assist.SvnAdmin{}
+	source, err := admin.ItemPath(argument.Source)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("源路径(%s)无效", argument.Source))
+		return
+	}
+	target, err := admin.ItemPath(parent + "/" + argument.Name)
+	if err != nil || target == parent {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("名称(%s)无效", argument.Name))
+		return
+	}
+	if len(argument.Message) < 1 {
+		if argument.Revision > 0 {
+			argument.Message = fmt.Sprintf("Create %s %s from %s@%d", argument.Kind, target, source, argument.Revision)
+		} else {
+			argument.Message = fmt.Sprintf("Create %s %s from %s", argument.Kind, target, source)
+		}
+	}
+
+	folder, err := s.newSvn().GetRepositoryPath(argument.Repository)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	author := s.getOperator(ctx)
+	revision, err := admin.Copy(folder, source, argument.Revision, target, argument.Message, author)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.LogInfo(fmt.Sprintf("svn: %s '%s%s' created from '%s' in r%d by %s",
+		argument.Kind, argument.Repository, target, source, revision, author))
+
+	ctx.Success(&model.SvnCommit{
+		Repository: argument.Repository,
+		Path:       target,
+		Revision:   revision,
+		Author:     author,
+		Message:    argument.Message,
+	})
+}
+
+func (s *Svn) NewBranchDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "文件夹")
+	function := catalog.AddFunction(method, uri, "新建分支或标签")
+	function.SetNote("在服务器端将源路径(默认/trunk)指定版本复制到/branches或/tags下, 提交者为登录账号, 未登录时为客户端地址; 目标已存在时失败")
+	function.SetInputJsonExample(&model.SvnBranchNew{
+		Repository: "MyRepo",
+		Kind:       model.SvnBranchKindTag,
+		Name:       "v1.0.0",
+		Source:     "/trunk",
+		Revision:   128,
+		Message:    "Release v1.0.0",
+	})
+	function.SetOutputDataExample(&model.SvnCommit{
+		Repository: "MyRepo",
+		Path:       "/tags/v1.0.0",
+		Revision:   129,
+		Author:     "admin",
+		Message:    "Release v1.0.0",
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
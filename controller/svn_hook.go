@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+)
+
+func (s *Svn) GetHookScripts(ctx gtype.Context, ps gtype.Params) {
+	hook := &assist.SvnHook{Library: s.cfg.Svn.Hook.Library}
+	scripts, err := hook.GetScripts()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(scripts)
+}
+
+func (s *Svn) GetHookScriptsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "获取脚本库")
+	function.SetNote("获取钩子脚本库(svn.hook.library)中审核通过的脚本")
+	function.SetOutputDataExample([]*model.SvnHookScript{
+		s.hookScriptExample(),
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Svn) GetHooks(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnHookFilter{}
+	ctx.GetJson(argument)
+
+	hook := &assist.SvnHook{Library: s.cfg.Svn.Hook.Library}
+	scripts, err := hook.GetScripts()
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	svn := s.newSvn()
+	repositories, err := s.getHookRepositories(svn, argument.Repository, len(argument.Repository) < 1)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	results := make([]*model.SvnHook, 0)
+	for i := 0; i < len(repositories); i++ {
+		repository := repositories[i]
+		folder, fe := svn.GetRepositoryPath(repository)
+		if fe != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(fe))
+			return
+		}
+		hooks, he := hook.GetHooks(repository, folder, scripts)
+		if he != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(fmt.Errorf("%s: %v", repository, he)))
+			return
+		}
+		for j := 0; j < len(hooks); j++ {
+			if argument.Differ && hooks[j].Status == model.SvnHookStatusSame {
+				continue
+			}
+			results = append(results, hooks[j])
+		}
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Svn) GetHooksDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "获取钩子列表")
+	function.SetNote("获取已安装的钩子并与脚本库比较, differ为true时仅返回与脚本库不同的钩子")
+	function.SetInputJsonExample(&model.SvnHookFilter{
+		Repository: "",
+		Differ:     true,
+	})
+	function.SetOutputDataExample([]*model.SvnHook{
+		{
+			Repository: "MyRepo",
+			Hook:       "pre-commit",
+			Script:     "commit-message",
+			Enabled:    true,
+			Sha256:     "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			Status:     model.SvnHookStatusDiffer,
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+}
+
+func (s *Svn) InstallHook(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnHookInstall{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Script) < 1 {
+		ctx.Error(gtype.ErrInput, "脚本名称(script)为空")
+		return
+	}
+
+	s.applyHook(ctx, &argument.SvnHookArgument, "install "+argument.Script, func(hook *assist.SvnHook, folder string) error {
+		return hook.Install(folder, argument.Hook, argument.Script)
+	})
+}
+
+func (s *Svn) InstallHookDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "安装钩子")
+	function.SetNote("将脚本库中的脚本安装到指定或所有存储库并启用, 非脚本库安装的原有钩子以.bak后缀保留; 已归档存储库的归档钩子不能替换; 返回每个存储库的结果")
+	function.SetInputJsonExample(&model.SvnHookInstall{
+		SvnHookArgument: model.SvnHookArgument{
+			Repository: "MyRepo",
+			All:        false,
+			Hook:       "pre-commit",
+		},
+		Script: "commit-message",
+	})
+	function.SetOutputDataExample(s.hookResultsExample())
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) EnableHook(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnHookArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	s.applyHook(ctx, argument, "enable", func(hook *assist.SvnHook, folder string) error {
+		return hook.Enable(folder, argument.Hook)
+	})
+}
+
+func (s *Svn) EnableHookDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "启用钩子")
+	function.SetNote("启用指定或所有存储库中已禁用的钩子, 返回每个存储库的结果")
+	function.SetInputJsonExample(s.hookArgumentExample())
+	function.SetOutputDataExample(s.hookResultsExample())
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) DisableHook(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnHookArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	s.applyHook(ctx, argument, "disable", func(hook *assist.SvnHook, folder string) error {
+		return hook.Disable(folder, argument.Hook)
+	})
+}
+
+func (s *Svn) DisableHookDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "禁用钩子")
+	function.SetNote("禁用指定或所有存储库中的钩子(脚本以.disabled后缀保留), 返回每个存储库的结果")
+	function.SetInputJsonExample(s.hookArgumentExample())
+	function.SetOutputDataExample(s.hookResultsExample())
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) RemoveHook(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnHookArgument{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	s.applyHook(ctx, argument, "remove", func(hook *assist.SvnHook, folder string) error {
+		return hook.Remove(folder, argument.Hook)
+	})
+}
+
+func (s *Svn) RemoveHookDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "钩子")
+	function := catalog.AddFunction(method, uri, "删除钩子")
+	function.SetNote("删除指定或所有存储库中的钩子(包括已禁用的), 返回每个存储库的结果")
+	function.SetInputJsonExample(s.hookArgumentExample())
+	function.SetOutputDataExample(s.hookResultsExample())
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) applyHook(ctx gtype.Context, argument *model.SvnHookArgument, action string, apply func(hook *assist.SvnHook, folder string) error) {
+	hook := &assist.SvnHook{Library: s.cfg.Svn.Hook.Library}
+	if !hook.IsHookName(argument.Hook) {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("钩子名称(%s)无效", argument.Hook))
+		return
+	}
+	if !argument.All && len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+
+	svn := s.newSvn()
+	repositories, err := s.getHookRepositories(svn, argument.Repository, argument.All)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	operator := s.getOperator(ctx)
+	results := make([]*model.SvnHookResult, 0)
+	for i := 0; i < len(repositories); i++ {
+		result := &model.SvnHookResult{
+			Repository: repositories[i],
+			Hook:       argument.Hook,
+		}
+		results = append(results, result)
+
+		folder, fe := svn.GetRepositoryPath(result.Repository)
+		if fe == nil {
+			fe = apply(hook, folder)
+		}
+		if fe != nil {
+			result.Error = fe.Error()
+			s.LogWarning(fmt.Sprintf("svn: hook '%s' of '%s' %s by %s fail: %v", argument.Hook, result.Repository, action, operator, fe))
+			continue
+		}
+		s.LogInfo(fmt.Sprintf("svn: hook '%s' of '%s' %s by %s", argument.Hook, result.Repository, action, operator))
+	}
+
+	ctx.Success(results)
+}
+
+func (s *Svn) getHookRepositories(svn assist.SvnBackend, repository string, all bool) ([]string, error) {
+	if !all {
+		return []string{repository}, nil
+	}
+
+	items, err := svn.GetRepositories(false)
+	if err != nil {
+		return nil, err
+	}
+	repositories := make([]string, 0, len(items))
+	for i := 0; i < len(items); i++ {
+		repositories = append(repositories, items[i].Repository)
+	}
+
+	return repositories, nil
+}
+
+func (s *Svn) hookScriptExample() *model.SvnHookScript {
+	return &model.SvnHookScript{
+		Hook:   "pre-commit",
+		Name:   "commit-message",
+		File:   "commit-message.bat",
+		Size:   512,
+		Sha256: "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+	}
+}
+
+func (s *Svn) hookArgumentExample() *model.SvnHookArgument {
+	return &model.SvnHookArgument{
+		Repository: "MyRepo",
+		All:        false,
+		Hook:       "pre-commit",
+	}
+}
+
+func (s *Svn) hookResultsExample() []*model.SvnHookResult {
+	return []*model.SvnHookResult{
+		{
+			Repository: "MyRepo",
+			Hook:       "pre-commit",
+			Error:      "",
+		},
+	}
+}
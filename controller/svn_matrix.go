@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"strings"
+	"time"
+)
+
+func (s *Svn) GetPermissionMatrix(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnPermissionMatrixArgument{}
+	ctx.GetJson(argument)
+	if len(argument.Format) < 1 {
+		argument.Format = model.SvnMatrixFormatJson
+	}
+	argument.Format = strings.ToLower(argument.Format)
+	if argument.Format != model.SvnMatrixFormatJson &&
+		argument.Format != model.SvnMatrixFormatCsv &&
+		argument.Format != model.SvnMatrixFormatXlsx {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("格式(%s)无效", argument.Format))
+		return
+	}
+
+	svn := s.newSvn()
+	items, err := svn.GetRepositories(false)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	repositories := make([]string, 0)
+	for i := 0; i < len(items); i++ {
+		if len(argument.Repository) > 0 && strings.ToLower(argument.Repository) != strings.ToLower(items[i].Repository) {
+			continue
+		}
+		repositories = append(repositories, items[i].Repository)
+	}
+	if len(argument.Repository) > 0 && len(repositories) < 1 {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)不存在", argument.Repository))
+		return
+	}
+
+	matrix := &assist.SvnMatrix{
+		Account:   argument.Account,
+		WriteOnly: argument.WriteOnly,
+	}
+	// the rules of all repositories are fetched once, the permissions of each path are resolved from them
+	rules, err := svn.GetAccessRules("")
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.markGroups(svn, len(rules), func(i int) (string, *bool) {
+		return rules[i].AccountId, &rules[i].Group
+	})
+	for i := 0; i < len(repositories); i++ {
+		folders, fe := svn.GetRepositoryFolders(repositories[i], "/", true)
+		if fe != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(fmt.Errorf("%s: %v", repositories[i], fe)))
+			return
+		}
+		matrix.AddFolders(repositories[i], folders, rules)
+	}
+
+	if argument.Format == model.SvnMatrixFormatJson {
+		ctx.Success(matrix.Rows)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	contentType := "text/csv; charset=utf-8"
+	if argument.Format == model.SvnMatrixFormatXlsx {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = matrix.WriteXlsx(buf)
+	} else {
+		err = matrix.WriteCsv(buf)
+	}
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	fileName := fmt.Sprintf("svn-permissions-%s.%s", time.Now().Format("20060102150405"), argument.Format)
+	response := ctx.Response()
+	response.Header().Set("Content-Type", contentType)
+	response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	response.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+	response.Write(buf.Bytes())
+}
+
+func (s *Svn) GetPermissionMatrixDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "导出访问权限矩阵")
+	function.SetNote("列出每个存储库及其所有文件夹上每个账号的访问权限(包括继承的); " +
+		"可按存储库、账号或仅读写权限过滤; 导出CSV文件时以=,+,-,@开头的内容加'前缀; " +
+		"format为csv或xlsx时下载文件, 否则返回数据")
+	function.SetInputJsonExample(&model.SvnPermissionMatrixArgument{
+		Repository: "MyRepo",
+		Account:    "",
+		WriteOnly:  true,
+		Format:     model.SvnMatrixFormatXlsx,
+	})
+	function.SetOutputDataExample([]*model.SvnPermissionMatrixRow{
+		{
+			Repository:  "MyRepo",
+			Path:        "/trunk",
+			AccountId:   "S-1-5-21-1114322273-403004966-1807125474-1105",
+			AccountName: "EXAMPLE\\Developers",
+			AccessLevel: model.SvnPermissionReadWrite,
+			Inherited:   true,
+			Group:       true,
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
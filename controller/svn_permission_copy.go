@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"strings"
+)
+
+func (s *Svn) CopyAccountPermissions(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnPermissionCopyAccount{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.SourceAccountId) < 1 {
+		ctx.Error(gtype.ErrInput, "源账号ID(sourceAccountId)为空")
+		return
+	}
+	if len(argument.TargetAccountId) < 1 {
+		ctx.Error(gtype.ErrInput, "目标账号ID(targetAccountId)为空")
+		return
+	}
+	if strings.ToLower(argument.SourceAccountId) == strings.ToLower(argument.TargetAccountId) {
+		ctx.Error(gtype.ErrInput, "源账号与目标账号相同")
+		return
+	}
+	copier, err := s.newPermissionCopy(argument.Mode)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	svn := s.newSvn()
+	source, err := s.getUserRules(svn, argument.SourceAccountId, argument.TargetAccountId)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	target, err := s.getUserRules(svn, argument.TargetAccountId, argument.TargetAccountId)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	description := fmt.Sprintf("account '%s' to '%s'", argument.SourceAccountId, argument.TargetAccountId)
+	ctx.Success(s.copyPermissions(ctx, svn, copier, source, target, argument.DryRun, description))
+}
+
+func (s *Svn) CopyAccountPermissionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "复制账号访问权限")
+	function.SetNote("将源账号的所有访问规则复制给目标账号(如新成员获得与某成员相同的权限); " +
+		"merge时仅添加目标账号没有的规则, replace时修改不同的规则并删除源账号没有的规则; dryRun为true时仅返回操作列表")
+	function.SetInputJsonExample(&model.SvnPermissionCopyAccount{
+		SourceAccountId: "S-1-5-21-1114322273-403004966-1807125474-1104",
+		TargetAccountId: "S-1-5-21-1114322273-403004966-1807125474-1108",
+		Mode:            model.SvnPermissionCopyModeMerge,
+		DryRun:          true,
+	})
+	function.SetOutputDataExample(&model.SvnPermissionCopyResult{
+		DryRun: true,
+		Actions: []*model.SvnPermissionAction{
+			{
+				Action:      model.SvnPermissionActionAdd,
+				Repository:  "MyRepo",
+				Path:        "/trunk",
+				AccountId:   "S-1-5-21-1114322273-403004966-1807125474-1108",
+				AccessLevel: model.SvnPermissionReadWrite,
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) CopyPathPermissions(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnPermissionCopyPath{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Repository) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(repository)为空")
+		return
+	}
+	if len(argument.TargetRepository) < 1 {
+		argument.TargetRepository = argument.Repository
+	}
+	admin := &assist.SvnAdmin{}
+	sourcePath, err := admin.ItemPath(argument.SourcePath)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("源路径(%s)无效", argument.SourcePath))
+		return
+	}
+	targetPath, err := admin.ItemPath(argument.TargetPath)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("目标路径(%s)无效", argument.TargetPath))
+		return
+	}
+	if strings.ToLower(argument.Repository) == strings.ToLower(argument.TargetRepository) && sourcePath == targetPath {
+		ctx.Error(gtype.ErrInput, "源路径与目标路径相同")
+		return
+	}
+	copier, err := s.newPermissionCopy(argument.Mode)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	svn := s.newSvn()
+	source, err := s.getPathRules(svn, argument.Repository, sourcePath, argument.TargetRepository, targetPath)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	target, err := s.getPathRules(svn, argument.TargetRepository, targetPath, argument.TargetRepository, targetPath)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	description := fmt.Sprintf("path '%s%s' to '%s%s'", argument.Repository, sourcePath, argument.TargetRepository, targetPath)
+	ctx.Success(s.copyPermissions(ctx, svn, copier, source, target, argument.DryRun, description))
+}
+
+func (s *Svn) CopyPathPermissionsDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "复制路径访问权限")
+	function.SetNote("将源路径上直接设置的访问规则(不包括继承的)复制到目标路径(如新建分支时复制/trunk的规则); " +
+		"merge时仅添加目标路径没有的规则, replace时修改不同的规则并删除源路径没有的规则; dryRun为true时仅返回操作列表")
+	function.SetInputJsonExample(&model.SvnPermissionCopyPath{
+		Repository: "MyRepo",
+		SourcePath: "/trunk",
+		TargetPath: "/branches/dev",
+		Mode:       model.SvnPermissionCopyModeReplace,
+		DryRun:     true,
+	})
+	function.SetOutputDataExample(&model.SvnPermissionCopyResult{
+		DryRun: true,
+		Actions: []*model.SvnPermissionAction{
+			{
+				Action:         model.SvnPermissionActionSet,
+				Repository:     "MyRepo",
+				Path:           "/branches/dev",
+				AccountId:      "S-1-5-21-1114322273-403004966-1807125474-1105",
+				AccountName:    "EXAMPLE\\Developers",
+				AccessLevel:    model.SvnPermissionReadWrite,
+				OldAccessLevel: model.SvnPermissionReadOnly,
+			},
+		},
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) newPermissionCopy(mode string) (*assist.SvnPermissionCopy, error) {
+	if len(mode) < 1 || mode == model.SvnPermissionCopyModeMerge {
+		return &assist.SvnPermissionCopy{Replace: false}, nil
+	} else if mode == model.SvnPermissionCopyModeReplace {
+		return &assist.SvnPermissionCopy{Replace: true}, nil
+	}
+
+	return nil, fmt.Errorf("模式(%s)无效", mode)
+}
+
+func (s *Svn) copyPermissions(ctx gtype.Context, svn assist.SvnBackend, copier *assist.SvnPermissionCopy,
+	source, target []*model.SvnAccessRule, dryRun bool, description string) *model.SvnPermissionCopyResult {
+	result := &model.SvnPermissionCopyResult{
+		DryRun:  dryRun,
+		Actions: copier.Plan(source, target),
+	}
+	if dryRun {
+		return result
+	}
+
+	result.Applied, result.Failed = copier.Apply(svn, result.Actions)
+	s.LogInfo(fmt.Sprintf("svn: permissions of %s copied by %s, %d applied, %d failed",
+		description, s.getOperator(ctx), result.Applied, result.Failed))
+
+	return result
+}
+
+// getUserRules returns the rules naming the account directly, as rules of the given account
+func (s *Svn) getUserRules(svn assist.SvnBackend, accountId, asAccountId string) ([]*model.SvnAccessRule, error) {
+	items, err := svn.GetUserPermissions(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*model.SvnAccessRule, 0, len(items))
+	for i := 0; i < len(items); i++ {
+		rules = append(rules, &model.SvnAccessRule{
+			Repository:  items[i].Repository,
+			Path:        items[i].Path,
+			AccountId:   asAccountId,
+			AccessLevel: items[i].AccessLevel,
+		})
+	}
+
+	return rules, nil
+}
+
+// getPathRules returns the rules set on the path itself, as rules of the given repository and path
+func (s *Svn) getPathRules(svn assist.SvnBackend, repository, path, asRepository, asPath string) ([]*model.SvnAccessRule, error) {
+	items, err := svn.GetPermissions(repository, path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*model.SvnAccessRule, 0, len(items))
+	for i := 0; i < len(items); i++ {
+		if items[i].Inherited {
+			continue
+		}
+		rules = append(rules, &model.SvnAccessRule{
+			Repository:  asRepository,
+			Path:        asPath,
+			AccountId:   items[i].AccountId,
+			AccountName: items[i].AccountName,
+			AccessLevel: items[i].AccessLevel,
+			Group:       items[i].Group,
+		})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/model"
+	"github.com/csby/gwsf/gtype"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	svnDeleteTokenExpiration = 5 * time.Minute
+)
+
+type svnDeleteToken struct {
+	repository string
+	expires    time.Time
+}
+
+func (s *Svn) GetRepositoryActivity(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnRepositoryNew{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(name)为空")
+		return
+	}
+
+	_, activity, err := s.getActivity(s.newSvn(), argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+
+	ctx.Success(activity)
+}
+
+func (s *Svn) GetRepositoryActivityDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "获取存储库活动信息")
+	function.SetNote("获取最新修订版本、最后提交时间、是否已归档及有效的锁")
+	function.SetInputJsonExample(&model.SvnRepositoryNew{
+		Name: "MyRepo",
+	})
+	function.SetOutputDataExample(s.activityExample())
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) DeleteRepository(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnRepositoryDelete{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(name)为空")
+		return
+	}
+	if s.isJobRunning(argument.Name) {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.Name))
+		return
+	}
+
+	svn := s.newSvn()
+	_, activity, err := s.getActivity(svn, argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	admin := &assist.SvnAdmin{}
+	err = admin.CheckActivity(activity, s.cfg.Svn.Lifecycle.RecentDays, argument.Force, time.Now())
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	if len(argument.Token) < 1 {
+		ctx.Success(s.newDeleteToken(activity))
+		return
+	}
+	if !s.useDeleteToken(argument.Token, argument.Name) {
+		ctx.Error(gtype.ErrInput, "确认令牌(token)无效或已过期")
+		return
+	}
+
+	err = svn.DeleteRepository(argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.LogInfo(fmt.Sprintf("svn: repository '%s' deleted by %s", argument.Name, s.getOperator(ctx)))
+
+	ctx.Success(nil)
+}
+
+func (s *Svn) DeleteRepositoryDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "删除存储库")
+	function.SetNote("token为空时检查存储库并返回确认令牌(5分钟内有效), 传入该令牌再次调用时删除存储库; 有锁或最近有提交(svn.lifecycle.recentDays, 可强制)时拒绝; 有正在运行的备份或还原任务时拒绝")
+	function.SetInputJsonExample(&model.SvnRepositoryDelete{
+		Name:  "MyRepo",
+		Token: "",
+		Force: false,
+	})
+	function.SetOutputDataExample(&model.SvnRepositoryDeleteConfirm{
+		Token:    gtype.NewGuid(),
+		Expires:  "2020-10-19 08:35:00",
+		Activity: s.activityExample(),
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) RenameRepository(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnRepositoryRename{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(name)为空")
+		return
+	}
+	if len(argument.NewName) < 1 {
+		ctx.Error(gtype.ErrInput, "新名称(newName)为空")
+		return
+	}
+	if s.isJobRunning(argument.Name) {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.Name))
+		return
+	}
+	if s.isJobRunning(argument.NewName) {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.NewName))
+		return
+	}
+
+	svn := s.newSvn()
+	rs, err := svn.GetRepositories(false)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	for i := 0; i < len(rs); i++ {
+		if strings.ToLower(argument.NewName) == strings.ToLower(rs[i].Repository) {
+			ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库名称(%s)已存在", argument.NewName))
+			return
+		}
+	}
+
+	_, activity, err := s.getActivity(svn, argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	admin := &assist.SvnAdmin{}
+	err = admin.CheckActivity(activity, s.cfg.Svn.Lifecycle.RecentDays, argument.Force, time.Now())
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	err = svn.RenameRepository(argument.Name, argument.NewName)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	s.LogInfo(fmt.Sprintf("svn: repository '%s' renamed to '%s' by %s", argument.Name, argument.NewName, s.getOperator(ctx)))
+
+	ctx.Success(argument.NewName)
+}
+
+func (s *Svn) RenameRepositoryDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "重命名存储库")
+	function.SetNote("成功时返回新名称; 有锁或最近有提交(svn.lifecycle.recentDays, 可强制)时拒绝; 有正在运行的备份或还原任务时拒绝")
+	function.SetInputJsonExample(&model.SvnRepositoryRename{
+		Name:    "MyRepo",
+		NewName: "MyRepoOld",
+		Force:   false,
+	})
+	function.SetOutputDataExample("MyRepoOld")
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) ArchiveRepository(ctx gtype.Context, ps gtype.Params) {
+	argument := &model.SvnRepositoryArchive{}
+	err := ctx.GetJson(argument)
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+	if len(argument.Name) < 1 {
+		ctx.Error(gtype.ErrInput, "存储库名称(name)为空")
+		return
+	}
+	if s.isJobRunning(argument.Name) {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)有正在运行的任务", argument.Name))
+		return
+	}
+
+	folder, activity, err := s.getActivity(s.newSvn(), argument.Name)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	if activity.Archived {
+		ctx.Error(gtype.ErrInput, fmt.Sprintf("存储库(%s)已归档", argument.Name))
+		return
+	}
+	admin := &assist.SvnAdmin{}
+	err = admin.CheckActivity(activity, s.cfg.Svn.Lifecycle.RecentDays, argument.Force, time.Now())
+	if err != nil {
+		ctx.Error(gtype.ErrInput, err)
+		return
+	}
+
+	result := &model.SvnRepositoryArchiveResult{
+		Activity: activity,
+	}
+	if argument.Dump {
+		result.DumpFile = filepath.Join(s.cfg.Svn.Lifecycle.DumpFolder,
+			fmt.Sprintf("%s-r%d-%s.dump", argument.Name, activity.Revisions, time.Now().Format("20060102150405")))
+		err = admin.Dump(folder, result.DumpFile)
+		if err != nil {
+			ctx.Error(gtype.ErrInternal.SetDetail(err))
+			return
+		}
+	}
+
+	err = admin.Archive(folder)
+	if err != nil {
+		ctx.Error(gtype.ErrInternal.SetDetail(err))
+		return
+	}
+	activity.Archived = true
+	s.LogInfo(fmt.Sprintf("svn: repository '%s' archived by %s", argument.Name, s.getOperator(ctx)))
+
+	ctx.Success(result)
+}
+
+func (s *Svn) ArchiveRepositoryDoc(doc gtype.Doc, method string, uri gtype.Uri) {
+	catalog := s.createCatalog(doc, "存储库")
+	function := catalog.AddFunction(method, uri, "归档存储库")
+	function.SetNote("安装拒绝提交、加锁及修改修订属性的钩子使存储库对所有人只读, dump为true时先导出转储文件; 有锁或最近有提交(svn.lifecycle.recentDays, 可强制)时拒绝; 有正在运行的备份或还原任务时拒绝")
+	function.SetInputJsonExample(&model.SvnRepositoryArchive{
+		Name:  "MyRepo",
+		Dump:  true,
+		Force: false,
+	})
+	function.SetOutputDataExample(&model.SvnRepositoryArchiveResult{
+		Activity: s.activityExample(),
+		DumpFile: "C:\\gwin\\data\\svn-dump\\MyRepo-r128-20201019083000.dump",
+	})
+	function.AddOutputError(gtype.ErrInternal)
+	function.AddOutputError(gtype.ErrInput)
+}
+
+func (s *Svn) getActivity(svn assist.SvnBackend, repository string) (string, *model.SvnRepositoryActivity, error) {
+	folder, err := svn.GetRepositoryPath(repository)
+	if err != nil {
+		return "", nil, err
+	}
+	admin := &assist.SvnAdmin{}
+	activity, err := admin.GetActivity(repository, folder)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return folder, activity, nil
+}
+
+func (s *Svn) newDeleteToken(activity *model.SvnRepositoryActivity) *model.SvnRepositoryDeleteConfirm {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	now := time.Now()
+	if s.deleteTokens == nil {
+		s.deleteTokens = make(map[string]*svnDeleteToken)
+	}
+	for k, v := range s.deleteTokens {
+		if now.After(v.expires) {
+			delete(s.deleteTokens, k)
+		}
+	}
+
+	token := &svnDeleteToken{
+		repository: activity.Repository,
+		expires:    now.Add(svnDeleteTokenExpiration),
+	}
+	id := gtype.NewGuid()
+	s.deleteTokens[id] = token
+
+	return &model.SvnRepositoryDeleteConfirm{
+		Token:    id,
+		Expires:  token.expires.Format("2006-01-02 15:04:05"),
+		Activity: activity,
+	}
+}
+
+func (s *Svn) useDeleteToken(id, repository string) bool {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	token, ok := s.deleteTokens[id]
+	if !ok {
+		return false
+	}
+	delete(s.deleteTokens, id)
+	if time.Now().After(token.expires) {
+		return false
+	}
+
+	return token.repository == repository
+}
+
+// getOperator returns the account of the login token, or the client address
+// when the request carries no token.
+func (s *Svn) getOperator(ctx gtype.Context) string {
+	token, ok := ctx.Token().(*gtype.Token)
+	if ok && token != nil && len(token.UserAccount) > 0 {
+		return token.UserAccount
+	}
+
+	addr := ctx.Request().RemoteAddr
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+func (s *Svn) activityExample() *model.SvnRepositoryActivity {
+	return &model.SvnRepositoryActivity{
+		Repository:     "MyRepo",
+		Revisions:      128,
+		LastCommitTime: "2020-06-01 17:20:00",
+		Archived:       false,
+		Locks:          []*model.SvnLock{},
+	}
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/csby/gwin/assist"
+	"github.com/csby/gwin/config"
+	"github.com/csby/gwin/model"
+	"os"
+	"strings"
+)
+
+const (
+	svnTemplateStandard = "standard"
+)
+
+func (s *Svn) getTemplate(name string) (*config.SvnTemplate, error) {
+	if len(name) < 1 {
+		name = svnTemplateStandard
+	}
+
+	var template *config.SvnTemplate
+	templates := s.cfg.Svn.Templates
+	for i := 0; i < len(templates); i++ {
+		if strings.ToLower(templates[i].Name) == strings.ToLower(name) {
+			template = &templates[i]
+			break
+		}
+	}
+	if template == nil {
+		if name != svnTemplateStandard {
+			return nil, fmt.Errorf("模板(%s)不存在", name)
+		}
+		template = &config.SvnTemplate{
+			Name:    svnTemplateStandard,
+			Folders: []string{"/branches", "/tags", "/trunk"},
+		}
+	}
+
+	admin := &assist.SvnAdmin{}
+	_, err := admin.Folders(template.Folders)
+	if err != nil {
+		return nil, fmt.Errorf("模板(%s)文件夹无效: %v", template.Name, err)
+	}
+	for i := 0; i < len(template.Permissions); i++ {
+		permission := template.Permissions[i]
+		if len(permission.AccountId) < 1 {
+			return nil, fmt.Errorf("模板(%s)访问权限账号ID为空", template.Name)
+		}
+		if permission.AccessLevel < model.SvnPermissionNoAccess || permission.AccessLevel > model.SvnPermissionReadWrite {
+			return nil, fmt.Errorf("模板(%s)访问级别(%d)无效", template.Name, permission.AccessLevel)
+		}
+		_, err = admin.ItemPath(permission.Path)
+		if err != nil {
+			return nil, fmt.Errorf("模板(%s)访问权限路径无效: %v", template.Name, err)
+		}
+	}
+
+	hook := &assist.SvnHook{Library: s.cfg.Svn.Hook.Library}
+	for i := 0; i < len(template.Hooks); i++ {
+		err = hook.CheckScript(template.Hooks[i].Name, template.Hooks[i].Script)
+		if err != nil {
+			return nil, fmt.Errorf("模板(%s)钩子(%s)脚本无效: %v", template.Name, template.Hooks[i].Name, err)
+		}
+	}
+	if len(template.Seed.Folder) > 0 {
+		_, err = os.Stat(template.Seed.Folder)
+		if err != nil {
+			return nil, fmt.Errorf("模板(%s)初始提交目录无效: %v", template.Name, err)
+		}
+	}
+
+	return template, nil
+}
+
+func (s *Svn) applyTemplate(svn assist.SvnBackend, repository string, template *config.SvnTemplate, author string) error {
+	if template == nil {
+		return nil
+	}
+
+	admin := &assist.SvnAdmin{}
+	for i := 0; i < len(template.Permissions); i++ {
+		permission := template.Permissions[i]
+		itemPath, err := admin.ItemPath(permission.Path)
+		if err != nil {
+			return err
+		}
+		err = svn.AddPermission(repository, itemPath, permission.AccountId, permission.AccessLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(template.Hooks) < 1 && len(template.Seed.Folder) < 1 {
+		return nil
+	}
+	folder, err := svn.GetRepositoryPath(repository)
+	if err != nil {
+		return err
+	}
+	// seed before hooks, so that the initial commit is not rejected by them
+	if len(template.Seed.Folder) > 0 {
+		message := template.Seed.Message
+		if len(message) < 1 {
+			message = fmt.Sprintf("Initial import from template %s", template.Name)
+		}
+		_, err = admin.Import(folder, template.Seed.Folder, template.Seed.Path, message, author)
+		if err != nil {
+			return err
+		}
+	}
+	hook := &assist.SvnHook{Library: s.cfg.Svn.Hook.Library}
+	for i := 0; i < len(template.Hooks); i++ {
+		err = hook.Install(folder, template.Hooks[i].Name, template.Hooks[i].Script)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
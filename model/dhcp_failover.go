@@ -0,0 +1,25 @@
+package model
+
+type DhcpFailover struct {
+	Name                string   `json:"name" note:"故障转移关系名称"`
+	PartnerServer       string   `json:"partnerServer" note:"伙伴服务器"`
+	Mode                string   `json:"mode" note:"模式: LoadBalance-负载平衡; HotStandby-热备用"`
+	State               string   `json:"state" note:"状态, 如: Normal, CommunicationInterrupted, PartnerDown"`
+	ServerRole          string   `json:"serverRole" note:"热备用模式下本服务器角色: Active, Standby"`
+	LoadBalancePercent  int      `json:"loadBalancePercent" note:"负载平衡模式下本服务器负载百分比"`
+	ReservePercent      int      `json:"reservePercent" note:"热备用模式下保留地址百分比"`
+	MaxClientLeadTime   string   `json:"maxClientLeadTime" note:"最长客户端提前期"`
+	AutoStateTransition bool     `json:"autoStateTransition" note:"是否自动切换到伙伴服务器关闭状态"`
+	StateSwitchInterval string   `json:"stateSwitchInterval" note:"自动切换状态间隔"`
+	ScopeIds            []string `json:"scopeIds" note:"包含的作用域ID"`
+}
+
+type DhcpFailoverReplicate struct {
+	Name    string `json:"name" note:"故障转移关系名称, 与作用域ID二选一"`
+	ScopeId string `json:"scopeId" note:"作用域ID, 与故障转移关系名称二选一"`
+}
+
+type DhcpFailoverReplication struct {
+	Replicated bool   `json:"replicated" note:"是否已复制到伙伴服务器"`
+	Error      string `json:"error" note:"复制失败时的错误信息, 成功时为空"`
+}
@@ -4,6 +4,9 @@ type DhcpFilter struct {
 	Allow   bool   `json:"allow" note:"ture-运行; false-拒绝"`
 	Address string `json:"address" note:"MAC地址"`
 	Comment string `json:"comment" note:"描述"`
+
+	Vendor     string `json:"vendor" note:"网卡厂商, 根据MAC地址OUI查询, 未加载OUI数据库(dhcp.ouiFile)时为空, 仅输出"`
+	Randomized bool   `json:"randomized" note:"是否为本地管理(随机)MAC地址, 仅输出"`
 }
 
 type DhcpFilterDelete struct {
@@ -15,3 +18,70 @@ type DhcpFilterModify struct {
 
 	Filter DhcpFilter `json:"filter" note:"新的筛选器"`
 }
+
+type DhcpFilterList struct {
+	Allow bool `json:"allow" note:"是否启用允许列表"`
+	Deny  bool `json:"deny" note:"是否启用拒绝列表"`
+}
+
+type DhcpFilterListArgument struct {
+	Allow *bool `json:"allow" note:"是否启用允许列表, 为空表示保持不变"`
+	Deny  *bool `json:"deny" note:"是否启用拒绝列表, 为空表示保持不变"`
+}
+
+type DhcpFilterResult struct {
+	Warning string `json:"warning" note:"警告信息, 如所编辑的列表未启用"`
+
+	Replication *DhcpFailoverReplication `json:"replication,omitempty" note:"推送到故障转移伙伴服务器的结果, 未启用自动复制或没有伙伴服务器时为空"`
+}
+
+const (
+	DhcpFilterFormatCsv  = "csv"
+	DhcpFilterFormatJson = "json"
+)
+
+type DhcpFilterExport struct {
+	Format string `json:"format" note:"文件格式: csv; json, 默认csv"`
+}
+
+type DhcpFilterFile struct {
+	Format  string `json:"format" note:"文件格式: csv; json"`
+	Content string `json:"content" note:"文件内容, csv列: allow,address,comment"`
+}
+
+type DhcpFilterImport struct {
+	DhcpFilterFile
+
+	Remove bool `json:"remove" note:"是否删除文件中不存在的筛选器"`
+	DryRun bool `json:"dryRun" note:"是否只预览差异而不修改"`
+}
+
+type DhcpFilterImportResult struct {
+	DryRun    bool          `json:"dryRun" note:"是否为预览"`
+	Added     []*DhcpFilter `json:"added" note:"新增的筛选器, 不含执行失败的项"`
+	Updated   []*DhcpFilter `json:"updated" note:"修改的筛选器, 不含执行失败的项"`
+	Removed   []*DhcpFilter `json:"removed" note:"删除的筛选器, 不含执行失败的项"`
+	Unchanged int           `json:"unchanged" note:"未变化的筛选器数量"`
+	Errors    []string      `json:"errors" note:"执行失败的错误信息"`
+
+	Replication *DhcpFailoverReplication `json:"replication,omitempty" note:"推送到故障转移伙伴服务器的结果, 未启用自动复制、没有伙伴服务器或预览时为空"`
+}
+
+const (
+	DhcpFilterStateModified   = "modified"
+	DhcpFilterStateUnchanged  = "unchanged"
+	DhcpFilterStateLost       = "lost"
+	DhcpFilterStateDuplicated = "duplicated"
+)
+
+type DhcpFilterModifyResult struct {
+	DhcpFilterResult
+
+	State    string      `json:"state" note:"服务器筛选器状态: modified-已修改; unchanged-未修改(已回滚); lost-原筛选器已删除且恢复失败; duplicated-新旧筛选器同时存在且回滚失败"`
+	Original *DhcpFilter `json:"original" note:"修改前的筛选器"`
+}
+
+type DhcpOui struct {
+	Source string `json:"source" note:"OUI数据库文件路径, 为空表示未加载"`
+	Count  int    `json:"count" note:"厂商记录数量"`
+}
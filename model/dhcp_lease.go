@@ -1,7 +1,64 @@
 package model
 
+const (
+	DhcpFamilyIpV4 = "ipv4"
+	DhcpFamilyIpV6 = "ipv6"
+)
+
+type DhcpFamilyArgument struct {
+	Family string `json:"family" note:"地址族: ipv4; ipv6; 为空表示全部"`
+}
+
+type DhcpLeaseArgument struct {
+	DhcpFamilyArgument
+
+	Vendor string `json:"vendor" note:"网卡厂商, 模糊匹配, 为空表示全部"`
+}
+
 type DhcpLease struct {
-	IpV4    string `json:"ipV4" note:"IPv4地址"`
-	Address string `json:"address" note:"MAC地址"`
-	Comment string `json:"comment" note:"描述"`
+	Family          string `json:"family" note:"地址族: ipv4; ipv6"`
+	ScopeId         string `json:"scopeId" note:"作用域ID, IPv6时为前缀"`
+	IpV4            string `json:"ipV4" note:"IPv4地址"`
+	IpV6            string `json:"ipV6" note:"IPv6地址"`
+	Address         string `json:"address" note:"MAC地址, IPv6时为空"`
+	Vendor          string `json:"vendor" note:"网卡厂商, 根据MAC地址OUI查询, 未加载OUI数据库(dhcp.ouiFile)时为空"`
+	Randomized      bool   `json:"randomized" note:"是否为本地管理(随机)MAC地址"`
+	ClientDuid      string `json:"clientDuid" note:"客户端DUID, 仅IPv6"`
+	Iaid            string `json:"iaid" note:"标识关联ID(IAID), 仅IPv6"`
+	HostName        string `json:"hostName" note:"主机名称"`
+	LeaseExpiryTime string `json:"leaseExpiryTime" note:"租用过期时间, 如: 2020-10-19 08:30:00, 保留地址未租用时为空"`
+	AddressState    string `json:"addressState" note:"地址状态: Active, Reservation, ActiveReservation, Declined, Expired等; IPv6时为地址类型"`
+	ClientType      string `json:"clientType" note:"客户端类型: Dhcp, BootP, Both, None等, 仅IPv4"`
+	Description     string `json:"description" note:"租用描述"`
+	Comment         string `json:"comment" note:"描述, 来自筛选器"`
+}
+
+type DhcpLeaseDelete struct {
+	IpV4    string `json:"ipV4" note:"IPv4地址, 与MAC地址二选一"`
+	Address string `json:"address" note:"MAC地址, 与IPv4地址二选一"`
+
+	Deny    bool   `json:"deny" note:"是否同时将MAC地址添加到拒绝列表"`
+	Comment string `json:"comment" note:"拒绝筛选器描述, deny为true时有效"`
+}
+
+type DhcpLeaseDeleteBatch struct {
+	ScopeId string `json:"scopeId" note:"作用域ID, 为空表示所有作用域"`
+	Denied  bool   `json:"denied" note:"true-只删除MAC地址在拒绝列表中的租用; false-删除作用域中的所有租用"`
+}
+
+type DhcpLeaseDeleteResult struct {
+	Leases      []*DhcpLease `json:"leases" note:"已删除的租用"`
+	ScopeErrors []string     `json:"scopeErrors" note:"获取租用失败的作用域错误信息, 这些作用域中的租用未被删除"`
+}
+
+type DhcpScopeLeases struct {
+	Family  string       `json:"family" note:"地址族: ipv4; ipv6"`
+	ScopeId string       `json:"scopeId" note:"作用域ID, IPv6时为前缀"`
+	Error   string       `json:"error" note:"获取租用失败时的错误信息, 成功时为空"`
+	Leases  []*DhcpLease `json:"leases" note:"地址租用列表"`
+}
+
+type DhcpLeaseList struct {
+	Scopes      []*DhcpScopeLeases `json:"scopes" note:"按作用域分组的地址租用"`
+	FilterError string             `json:"filterError" note:"获取筛选器描述失败时的错误信息, 成功时为空"`
 }
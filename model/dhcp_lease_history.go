@@ -0,0 +1,18 @@
+package model
+
+type DhcpLeaseRecord struct {
+	ScopeId   string `json:"scopeId" note:"作用域ID"`
+	IpV4      string `json:"ipV4" note:"IPv4地址"`
+	Address   string `json:"address" note:"MAC地址"`
+	HostName  string `json:"hostName" note:"主机名称"`
+	StartTime string `json:"startTime" note:"首次采样到租用的时间"`
+	EndTime   string `json:"endTime" note:"最后采样到租用的时间"`
+	Active    bool   `json:"active" note:"是否仍在租用"`
+}
+
+type DhcpLeaseHistoryFilter struct {
+	IpV4      string `json:"ipV4" note:"IPv4地址, 为空表示全部"`
+	Address   string `json:"address" note:"MAC地址, 为空表示全部"`
+	StartTime string `json:"startTime" note:"开始时间, 如: 2020-10-13 00:00:00, 为空表示不限"`
+	EndTime   string `json:"endTime" note:"结束时间, 如: 2020-10-14 00:00:00, 为空表示不限"`
+}
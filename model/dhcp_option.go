@@ -0,0 +1,53 @@
+package model
+
+const (
+	DhcpOptionLevelServer      = "server"
+	DhcpOptionLevelScope       = "scope"
+	DhcpOptionLevelReservation = "reservation"
+)
+
+const (
+	DhcpOptionRouter         = 3
+	DhcpOptionDnsServers     = 6
+	DhcpOptionDomainName     = 15
+	DhcpOptionNtpServers     = 42
+	DhcpOptionBootServerName = 66
+	DhcpOptionBootFileName   = 67
+)
+
+type DhcpOption struct {
+	OptionId    int      `json:"optionId" note:"选项ID: 3-路由器; 6-DNS服务器; 15-DNS域名; 42-NTP服务器; 66-启动服务器主机名; 67-启动文件名"`
+	Name        string   `json:"name" note:"选项名称"`
+	Type        string   `json:"type" note:"值类型: IPv4Address, String, DWord等"`
+	Value       []string `json:"value" note:"选项值"`
+	VendorClass string   `json:"vendorClass" note:"供应商类, 为空表示标准选项"`
+	UserClass   string   `json:"userClass" note:"用户类"`
+	Level       string   `json:"level" note:"来源级别: server-服务器; scope-作用域; reservation-保留地址"`
+}
+
+type DhcpOptionArgument struct {
+	ScopeId    string `json:"scopeId" note:"作用域ID, 为空且保留地址为空时表示服务器级别"`
+	ReservedIp string `json:"reservedIp" note:"保留地址, 不为空时表示保留地址级别"`
+}
+
+type DhcpOptionEdit struct {
+	DhcpOptionArgument
+
+	OptionId    int      `json:"optionId" required:"true" note:"选项ID"`
+	Value       []string `json:"value" required:"true" note:"选项值"`
+	VendorClass string   `json:"vendorClass" note:"供应商类, 为空表示标准选项"`
+}
+
+type DhcpOptionDelete struct {
+	DhcpOptionArgument
+
+	OptionId    int    `json:"optionId" required:"true" note:"选项ID"`
+	VendorClass string `json:"vendorClass" note:"供应商类, 为空表示标准选项"`
+}
+
+type DhcpClass struct {
+	Name        string `json:"name" note:"类名称"`
+	Type        string `json:"type" note:"类型: Vendor-供应商类; User-用户类"`
+	Data        string `json:"data" note:"类数据"`
+	Description string `json:"description" note:"描述"`
+}
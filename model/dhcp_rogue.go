@@ -0,0 +1,33 @@
+package model
+
+const (
+	DhcpRogueKindUnknown = "unknown"
+	DhcpRogueKindDenied  = "denied"
+)
+
+type DhcpRogueArgument struct {
+	IdleDays int `json:"idleDays" note:"允许列表中超过该天数未租用地址的筛选器视为闲置, 0表示使用配置值"`
+}
+
+type DhcpRogueFilter struct {
+	DhcpFilter
+
+	LastSeen string `json:"lastSeen" note:"最后租用时间, 为空表示从未记录到租用"`
+}
+
+type DhcpRogueReport struct {
+	CheckTime string             `json:"checkTime" note:"检查时间"`
+	IdleDays  int                `json:"idleDays" note:"闲置天数"`
+	History   bool               `json:"history" note:"是否根据租用历史计算闲置, false时只根据当前租用计算"`
+	Unknown   []*DhcpLease       `json:"unknown" note:"MAC地址不在允许列表中的租用"`
+	Denied    []*DhcpLease       `json:"denied" note:"MAC地址在拒绝列表中的租用"`
+	Idle      []*DhcpRogueFilter `json:"idle" note:"闲置的允许列表筛选器"`
+
+	ScopeErrors []string `json:"scopeErrors" note:"获取租用失败的作用域错误信息, 不为空时报告不完整, 闲置筛选器可能不准确"`
+}
+
+type DhcpRogueEvent struct {
+	Time  string     `json:"time" note:"发现时间"`
+	Kind  string     `json:"kind" note:"类型: unknown-不在允许列表中; denied-在拒绝列表中"`
+	Lease *DhcpLease `json:"lease" note:"地址租用"`
+}
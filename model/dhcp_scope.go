@@ -0,0 +1,48 @@
+package model
+
+type DhcpScope struct {
+	Family     string `json:"family" note:"地址族: ipv4; ipv6"`
+	ScopeId    string `json:"scopeId" note:"作用域ID, IPv6时为前缀"`
+	Name       string `json:"name" note:"作用域名称"`
+	SubnetMask string `json:"subnetMask" note:"子网掩码, 仅IPv4"`
+	StartRange string `json:"startRange" note:"起始地址, 仅IPv4"`
+	EndRange   string `json:"endRange" note:"结束地址, 仅IPv4"`
+	State      string `json:"state" note:"状态: Active, Inactive"`
+}
+
+type DhcpScopeArgument struct {
+	ScopeId string `json:"scopeId" required:"true" note:"作用域ID"`
+}
+
+type DhcpExclusion struct {
+	ScopeId    string `json:"scopeId" required:"true" note:"作用域ID"`
+	StartRange string `json:"startRange" required:"true" note:"起始地址"`
+	EndRange   string `json:"endRange" required:"true" note:"结束地址"`
+}
+
+type DhcpReservation struct {
+	Family      string `json:"family" note:"地址族: ipv4; ipv6"`
+	ScopeId     string `json:"scopeId" required:"true" note:"作用域ID, IPv6时为前缀"`
+	IpV4        string `json:"ipV4" note:"IPv4地址"`
+	IpV6        string `json:"ipV6" note:"IPv6地址"`
+	Address     string `json:"address" note:"MAC地址, 仅IPv4"`
+	ClientDuid  string `json:"clientDuid" note:"客户端DUID, 仅IPv6"`
+	Iaid        string `json:"iaid" note:"标识关联ID(IAID), 仅IPv6"`
+	Name        string `json:"name" note:"保留名称"`
+	Description string `json:"description" note:"描述"`
+}
+
+type DhcpReservationArgument struct {
+	DhcpFamilyArgument
+
+	ScopeId string `json:"scopeId" note:"作用域ID, IPv6时为前缀, 为空表示所有作用域"`
+}
+
+type DhcpReservationDelete struct {
+	IpV4 string `json:"ipV4" note:"IPv4地址, 与IPv6地址二选一"`
+	IpV6 string `json:"ipV6" note:"IPv6地址, 与IPv4地址二选一"`
+}
+
+type DhcpReservationResult struct {
+	Replication *DhcpFailoverReplication `json:"replication,omitempty" note:"故障转移复制结果, 未启用自动复制或作用域不在故障转移关系中时为空"`
+}
@@ -0,0 +1,24 @@
+package model
+
+const (
+	DhcpAlarmLevelNone     = ""
+	DhcpAlarmLevelWarning  = "warning"
+	DhcpAlarmLevelCritical = "critical"
+)
+
+type DhcpScopeStatistics struct {
+	ScopeId    string  `json:"scopeId" note:"作用域ID"`
+	Total      int     `json:"total" note:"地址总数"`
+	InUse      int     `json:"inUse" note:"已使用地址数"`
+	Free       int     `json:"free" note:"空闲地址数"`
+	Reserved   int     `json:"reserved" note:"保留地址数"`
+	Pending    int     `json:"pending" note:"待确认地址数"`
+	Percentage float64 `json:"percentage" note:"使用率(%)"`
+	Level      string  `json:"level" note:"告警级别: 空-正常; warning-警告; critical-严重"`
+}
+
+type DhcpStatus struct {
+	CheckTime string                 `json:"checkTime" note:"最近检查时间"`
+	Error     string                 `json:"error" note:"最近检查错误信息"`
+	Alarms    []*DhcpScopeStatistics `json:"alarms" note:"使用率超过阈值的作用域"`
+}
@@ -0,0 +1,50 @@
+package model
+
+const (
+	DnsReconcileKindStale   = "stale"
+	DnsReconcileKindMissing = "missing"
+	DnsReconcileKindOrphan  = "orphan"
+)
+
+const (
+	DnsReconcileActionAdd    = "add"
+	DnsReconcileActionDelete = "delete"
+	DnsReconcileActionUpdate = "update"
+)
+
+type DnsReconcileArgument struct {
+	ZoneName string `json:"zoneName" note:"域名, 为空表示所有配置的域名(dns.zoomNames)"`
+}
+
+type DnsReconcileItem struct {
+	Id       string `json:"id" note:"项ID, 执行核对建议时使用"`
+	Kind     string `json:"kind" note:"类型: stale-记录指向主机已不再租用的地址; missing-租用没有对应记录; orphan-记录位于作用域范围内但没有有效租用"`
+	Action   string `json:"action" note:"建议操作: add-添加记录; delete-删除记录; update-将记录修改为租用地址"`
+	ZoneName string `json:"zoneName" note:"域名"`
+	Name     string `json:"name" note:"记录名称"`
+	Data     string `json:"data" note:"记录当前数据(IPv4地址), missing时为空"`
+	IpV4     string `json:"ipV4" note:"租用地址, orphan时为空"`
+	Address  string `json:"address" note:"租用的MAC地址"`
+	HostName string `json:"hostName" note:"租用的主机名"`
+}
+
+type DnsReconcileReport struct {
+	CheckTime string              `json:"checkTime" note:"检查时间"`
+	ZoneNames []string            `json:"zoneNames" note:"检查的域名"`
+	Stale     []*DnsReconcileItem `json:"stale" note:"指向主机已不再租用地址的记录"`
+	Missing   []*DnsReconcileItem `json:"missing" note:"没有对应记录的租用"`
+	Orphan    []*DnsReconcileItem `json:"orphan" note:"位于作用域范围内但没有有效租用的记录"`
+
+	ScopeErrors []string `json:"scopeErrors" note:"获取租用失败的作用域错误信息, 这些作用域不参与核对"`
+}
+
+type DnsReconcileFix struct {
+	DnsReconcileArgument
+
+	Ids []string `json:"ids" required:"true" note:"要执行的项ID, 从相同域名的核对报告中选择"`
+}
+
+type DnsReconcileFixResult struct {
+	Applied []*DnsReconcileItem `json:"applied" note:"执行成功的项"`
+	Errors  []string            `json:"errors" note:"执行失败或已不在当前报告中的项的错误信息"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "strings"
+
+type MsAdGroup struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Account     string `json:"account"`
+	Description string `json:"description"`
+	Members     int    `json:"members" note:"直接成员数量"`
+}
+
+type MsAdGroupCollection []*MsAdGroup
+
+func (x MsAdGroupCollection) Len() int { return len(x) }
+func (x MsAdGroupCollection) Less(i, j int) bool {
+	return strings.ToLower(x[i].Account) < strings.ToLower(x[j].Account)
+}
+func (x MsAdGroupCollection) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
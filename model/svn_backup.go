@@ -0,0 +1,62 @@
+package model
+
+const (
+	SvnBackupKindFull        = "full"
+	SvnBackupKindIncremental = "incremental"
+	SvnBackupKindHotcopy     = "hotcopy"
+)
+
+const (
+	SvnJobKindBackup  = "backup"
+	SvnJobKindRestore = "restore"
+)
+
+const (
+	SvnJobStatusRunning   = "running"
+	SvnJobStatusSucceeded = "succeeded"
+	SvnJobStatusFailed    = "failed"
+)
+
+type SvnBackup struct {
+	Id           string `json:"id" note:"备份ID"`
+	Repository   string `json:"repository" note:"存储库名称"`
+	Kind         string `json:"kind" note:"类型: full-完整转储; incremental-增量转储; hotcopy-热拷贝"`
+	FromRevision int    `json:"fromRevision" note:"起始修订版本"`
+	ToRevision   int    `json:"toRevision" note:"结束修订版本"`
+	File         string `json:"file" note:"备份文件名称(gzip压缩)"`
+	Size         int64  `json:"size" note:"文件大小(字节)"`
+	Sha256       string `json:"sha256" note:"文件SHA256校验和"`
+	Time         string `json:"time" note:"备份时间"`
+}
+
+type SvnBackupNew struct {
+	Repository string `json:"repository" required:"true" note:"存储库名称"`
+	Kind       string `json:"kind" note:"类型: full-完整转储(默认); incremental-从上次备份之后增量转储; hotcopy-热拷贝"`
+}
+
+type SvnBackupArgument struct {
+	Repository string `json:"repository" note:"存储库名称, 为空表示所有存储库"`
+}
+
+type SvnBackupRestore struct {
+	Id   string `json:"id" required:"true" note:"备份ID, 增量备份时自动依次还原之前的完整及增量备份"`
+	Name string `json:"name" required:"true" note:"新存储库名称"`
+}
+
+type SvnJob struct {
+	Id         string     `json:"id" note:"任务ID"`
+	Kind       string     `json:"kind" note:"类型: backup-备份; restore-还原"`
+	Repository string     `json:"repository" note:"存储库名称, 还原时为新存储库名称"`
+	Status     string     `json:"status" note:"状态: running-运行中; succeeded-成功; failed-失败"`
+	Revision   int        `json:"revision" note:"已处理的修订版本数量"`
+	Total      int        `json:"total" note:"需要处理的修订版本数量"`
+	Progress   float64    `json:"progress" note:"进度(%)"`
+	StartTime  string     `json:"startTime" note:"开始时间"`
+	EndTime    string     `json:"endTime" note:"结束时间, 运行中为空"`
+	Error      string     `json:"error" note:"失败时的错误信息"`
+	Backup     *SvnBackup `json:"backup" note:"备份信息, 备份成功或还原时有效"`
+}
+
+type SvnJobArgument struct {
+	Id string `json:"id" note:"任务ID, 为空表示所有任务"`
+}
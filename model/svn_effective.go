@@ -0,0 +1,27 @@
+package model
+
+type SvnAccessRule struct {
+	Repository  string `json:"repository" note:"存储库名称, *表示所有存储库"`
+	Path        string `json:"path" note:"路径"`
+	AccountId   string `json:"accountId" note:"账号ID"`
+	AccountName string `json:"accountName" note:"账号名称"`
+	AccessLevel int    `json:"accessLevel" note:"访问权限: 0-无; 1-只读; 2-读写"`
+	Group       bool   `json:"group" note:"是否为组账号"`
+}
+
+type SvnEffectiveArgument struct {
+	Repository string `json:"repository" required:"true" note:"存储库名称"`
+	Path       string `json:"path" required:"true" note:"路径"`
+	AccountId  string `json:"accountId" required:"true" note:"用户账号ID, authz时$anonymous表示匿名用户"`
+}
+
+type SvnEffectivePermission struct {
+	Repository  string           `json:"repository" note:"存储库名称"`
+	Path        string           `json:"path" note:"路径"`
+	AccountId   string           `json:"accountId" note:"用户账号ID"`
+	AccessLevel int              `json:"accessLevel" note:"有效访问权限: 0-无; 1-只读; 2-读写"`
+	Groups      []string         `json:"groups" note:"用户所属的组(包括嵌套的组)"`
+	Rule        *SvnAccessRule   `json:"rule" note:"决定有效访问权限的规则, 没有匹配的规则时为空"`
+	Matches     []*SvnAccessRule `json:"matches" note:"从该路径到根路径匹配该用户的所有规则, 按计算顺序排列"`
+	Reason      string           `json:"reason" note:"计算说明"`
+}
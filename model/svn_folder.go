@@ -0,0 +1,29 @@
+package model
+
+const (
+	SvnBranchKindBranch = "branch"
+	SvnBranchKindTag    = "tag"
+)
+
+type SvnFolderNew struct {
+	Repository string `json:"repository" required:"true" note:"存储库名称"`
+	Path       string `json:"path" required:"true" note:"文件夹路径, 如: /trunk/docs, 不存在的上级文件夹自动创建"`
+	Message    string `json:"message" note:"提交日志, 为空时使用默认日志"`
+}
+
+type SvnBranchNew struct {
+	Repository string `json:"repository" required:"true" note:"存储库名称"`
+	Kind       string `json:"kind" note:"类型: branch-分支(默认), 复制到/branches; tag-标签, 复制到/tags"`
+	Name       string `json:"name" required:"true" note:"分支或标签名称"`
+	Source     string `json:"source" note:"源路径, 为空时为/trunk"`
+	Revision   int    `json:"revision" note:"源修订版本号, 0表示最新版本"`
+	Message    string `json:"message" note:"提交日志, 为空时使用默认日志"`
+}
+
+type SvnCommit struct {
+	Repository string `json:"repository" note:"存储库名称"`
+	Path       string `json:"path" note:"创建的路径"`
+	Revision   int    `json:"revision" note:"提交的修订版本号"`
+	Author     string `json:"author" note:"提交者, 为登录账号, 未登录时为客户端地址"`
+	Message    string `json:"message" note:"提交日志"`
+}
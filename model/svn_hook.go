@@ -0,0 +1,48 @@
+package model
+
+const (
+	SvnHookStatusSame    = "same"
+	SvnHookStatusDiffer  = "differ"
+	SvnHookStatusMissing = "missing"
+	SvnHookStatusCustom  = "custom"
+)
+
+type SvnHookScript struct {
+	Hook   string `json:"hook" note:"钩子名称, 如: pre-commit"`
+	Name   string `json:"name" note:"脚本名称"`
+	File   string `json:"file" note:"脚本文件名"`
+	Size   int64  `json:"size" note:"文件大小(字节)"`
+	Sha256 string `json:"sha256" note:"SHA256校验和"`
+}
+
+type SvnHook struct {
+	Repository string `json:"repository" note:"存储库名称"`
+	Hook       string `json:"hook" note:"钩子名称, 如: pre-commit"`
+	Script     string `json:"script" note:"脚本库中的脚本名称, 非脚本库安装的钩子为空"`
+	Enabled    bool   `json:"enabled" note:"是否启用"`
+	Sha256     string `json:"sha256" note:"SHA256校验和"`
+	Status     string `json:"status" note:"与脚本库比较: same-相同; differ-不同; missing-脚本库中不存在; custom-非脚本库安装"`
+}
+
+type SvnHookFilter struct {
+	Repository string `json:"repository" note:"存储库名称, 为空时表示所有存储库"`
+	Differ     bool   `json:"differ" note:"是否仅返回与脚本库不同的钩子"`
+}
+
+type SvnHookArgument struct {
+	Repository string `json:"repository" note:"存储库名称, all为true时忽略"`
+	All        bool   `json:"all" note:"是否应用到所有存储库"`
+	Hook       string `json:"hook" required:"true" note:"钩子名称, 如: pre-commit"`
+}
+
+type SvnHookInstall struct {
+	SvnHookArgument
+
+	Script string `json:"script" required:"true" note:"脚本库中的脚本名称"`
+}
+
+type SvnHookResult struct {
+	Repository string `json:"repository" note:"存储库名称"`
+	Hook       string `json:"hook" note:"钩子名称"`
+	Error      string `json:"error" note:"错误信息, 成功时为空"`
+}
@@ -0,0 +1,24 @@
+package model
+
+const (
+	SvnMatrixFormatJson = "json"
+	SvnMatrixFormatCsv  = "csv"
+	SvnMatrixFormatXlsx = "xlsx"
+)
+
+type SvnPermissionMatrixArgument struct {
+	Repository string `json:"repository" note:"存储库名称, 为空时表示所有存储库"`
+	Account    string `json:"account" note:"账号ID或名称, 为空时表示所有账号"`
+	WriteOnly  bool   `json:"writeOnly" note:"是否仅包含读写权限"`
+	Format     string `json:"format" note:"格式: json-返回数据(默认); csv-下载CSV文件; xlsx-下载Excel文件"`
+}
+
+type SvnPermissionMatrixRow struct {
+	Repository  string `json:"repository" note:"存储库名称"`
+	Path        string `json:"path" note:"文件夹路径"`
+	AccountId   string `json:"accountId" note:"账号ID"`
+	AccountName string `json:"accountName" note:"账号名称"`
+	AccessLevel int    `json:"accessLevel" note:"访问权限: 0-无; 1-只读; 2-读写"`
+	Inherited   bool   `json:"inherited" note:"是否继承"`
+	Group       bool   `json:"group" note:"是否为组账号"`
+}
@@ -5,6 +5,7 @@ type SvnPermission struct {
 	AccountName string `json:"accountName" note:"账号名称"`
 	AccessLevel int    `json:"accessLevel" note:"访问权限: 0-无; 1-只读; 2-读写"`
 	Inherited   bool   `json:"inherited" note:"是否继承"`
+	Group       bool   `json:"group" note:"是否为组账号"`
 }
 
 type SvnPermissionUser struct {
@@ -20,7 +21,8 @@ type SvnPermissionUserArgument struct {
 type SvnPermissionArgument struct {
 	Repository string `json:"repository" required:"true" note:"存储库名称"`
 	Path       string `json:"path" required:"true" note:"路径"`
-	AccountId  string `json:"accountId" required:"true" note:"账号ID"`
+	AccountId  string `json:"accountId" required:"true" note:"账号ID, 可以是用户或组"`
+	Group      bool   `json:"group" note:"是否为组账号, authz服务时组名自动添加@前缀"`
 }
 
 type SvnPermissionArgumentEdit struct {
@@ -0,0 +1,47 @@
+package model
+
+const (
+	SvnPermissionCopyModeMerge   = "merge"
+	SvnPermissionCopyModeReplace = "replace"
+)
+
+const (
+	SvnPermissionActionAdd    = "add"
+	SvnPermissionActionSet    = "set"
+	SvnPermissionActionRemove = "remove"
+	SvnPermissionActionKeep   = "keep"
+)
+
+type SvnPermissionCopyAccount struct {
+	SourceAccountId string `json:"sourceAccountId" required:"true" note:"源账号ID"`
+	TargetAccountId string `json:"targetAccountId" required:"true" note:"目标账号ID"`
+	Mode            string `json:"mode" note:"模式: merge-合并(默认), 仅添加目标没有的规则; replace-替换, 目标规则与源规则完全一致"`
+	DryRun          bool   `json:"dryRun" note:"是否仅预览而不修改"`
+}
+
+type SvnPermissionCopyPath struct {
+	Repository       string `json:"repository" required:"true" note:"源存储库名称"`
+	SourcePath       string `json:"sourcePath" required:"true" note:"源路径, 如: /trunk"`
+	TargetRepository string `json:"targetRepository" note:"目标存储库名称, 为空时与源存储库相同"`
+	TargetPath       string `json:"targetPath" required:"true" note:"目标路径, 如: /branches/dev"`
+	Mode             string `json:"mode" note:"模式: merge-合并(默认), 仅添加目标没有的规则; replace-替换, 目标规则与源规则完全一致"`
+	DryRun           bool   `json:"dryRun" note:"是否仅预览而不修改"`
+}
+
+type SvnPermissionAction struct {
+	Action         string `json:"action" note:"操作: add-添加; set-修改; remove-删除; keep-保持不变"`
+	Repository     string `json:"repository" note:"存储库名称"`
+	Path           string `json:"path" note:"路径"`
+	AccountId      string `json:"accountId" note:"账号ID"`
+	AccountName    string `json:"accountName" note:"账号名称"`
+	AccessLevel    int    `json:"accessLevel" note:"操作后的访问权限: 0-无; 1-只读; 2-读写"`
+	OldAccessLevel int    `json:"oldAccessLevel" note:"操作前的访问权限, 仅set、remove及keep时有效"`
+	Error          string `json:"error" note:"错误信息, 成功或预览时为空"`
+}
+
+type SvnPermissionCopyResult struct {
+	DryRun  bool                   `json:"dryRun" note:"是否仅预览"`
+	Applied int                    `json:"applied" note:"成功执行的操作数量"`
+	Failed  int                    `json:"failed" note:"失败的操作数量"`
+	Actions []*SvnPermissionAction `json:"actions" note:"操作列表"`
+}
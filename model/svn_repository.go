@@ -19,5 +19,49 @@ type SvnRepository struct {
 }
 
 type SvnRepositoryNew struct {
-	Name string `json:"name" required:"true" note:"名称"`
+	Name     string `json:"name" required:"true" note:"名称"`
+	Template string `json:"template" note:"模板名称(svn.templates), 为空时使用standard模板(branches,tags,trunk)"`
+}
+
+type SvnLock struct {
+	Path    string `json:"path" note:"锁定的路径"`
+	Owner   string `json:"owner" note:"锁定者"`
+	Created string `json:"created" note:"锁定时间"`
+}
+
+type SvnRepositoryActivity struct {
+	Repository     string     `json:"repository" note:"存储库名称"`
+	Revisions      int        `json:"revisions" note:"最新修订版本号"`
+	LastCommitTime string     `json:"lastCommitTime" note:"最后提交时间, 没有提交时为空"`
+	Archived       bool       `json:"archived" note:"是否已归档(只读)"`
+	Locks          []*SvnLock `json:"locks" note:"有效的锁"`
+}
+
+type SvnRepositoryDelete struct {
+	Name  string `json:"name" required:"true" note:"名称"`
+	Token string `json:"token" note:"确认令牌, 为空时返回确认令牌而不删除"`
+	Force bool   `json:"force" note:"是否忽略最近提交检查, 有锁时仍然拒绝"`
+}
+
+type SvnRepositoryDeleteConfirm struct {
+	Token    string                 `json:"token" note:"确认令牌, 再次调用时传入以确认删除"`
+	Expires  string                 `json:"expires" note:"令牌过期时间"`
+	Activity *SvnRepositoryActivity `json:"activity" note:"存储库活动信息"`
+}
+
+type SvnRepositoryRename struct {
+	Name    string `json:"name" required:"true" note:"名称"`
+	NewName string `json:"newName" required:"true" note:"新名称"`
+	Force   bool   `json:"force" note:"是否忽略最近提交检查, 有锁时仍然拒绝"`
+}
+
+type SvnRepositoryArchive struct {
+	Name  string `json:"name" required:"true" note:"名称"`
+	Dump  bool   `json:"dump" note:"归档前是否导出转储文件"`
+	Force bool   `json:"force" note:"是否忽略最近提交检查, 有锁时仍然拒绝"`
+}
+
+type SvnRepositoryArchiveResult struct {
+	Activity *SvnRepositoryActivity `json:"activity" note:"存储库活动信息"`
+	DumpFile string                 `json:"dumpFile" note:"转储文件路径, 未导出时为空"`
 }
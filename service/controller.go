@@ -15,6 +15,10 @@ type Controller struct {
 func (s *Controller) Init(h *Handler) {
 	s.opt = controller.NewOpt(log, cfg)
 	s.dhcp = controller.NewDhcp(log, cfg)
+	if cfg.Dhcp.Enable {
+		s.dhcp.LoadOui()
+		s.dhcp.StartMonitor()
+	}
 	s.dns = controller.NewDns(log, cfg)
 	s.svn = controller.NewSvn(log, cfg)
 }
@@ -30,9 +34,64 @@ func (s *Controller) InitRouting(router gtype.Router, path *gtype.Path) {
 			s.dhcp.DelFilter, s.dhcp.DelFilterDoc)
 		router.POST(path.Uri("/dhcp/filter/mod"), nil,
 			s.dhcp.ModFilter, s.dhcp.ModFilterDoc)
+		router.POST(path.Uri("/dhcp/filter/policy/get"), nil,
+			s.dhcp.GetFilterList, s.dhcp.GetFilterListDoc)
+		router.POST(path.Uri("/dhcp/filter/policy/set"), nil,
+			s.dhcp.SetFilterList, s.dhcp.SetFilterListDoc)
+		router.POST(path.Uri("/dhcp/filter/export"), nil,
+			s.dhcp.ExportFilters, s.dhcp.ExportFiltersDoc)
+		router.POST(path.Uri("/dhcp/filter/import"), nil,
+			s.dhcp.ImportFilters, s.dhcp.ImportFiltersDoc)
 
 		router.POST(path.Uri("/dhcp/lease/list"), nil,
 			s.dhcp.GetLeases, s.dhcp.GetLeasesDoc)
+		router.POST(path.Uri("/dhcp/lease/del"), nil,
+			s.dhcp.DelLease, s.dhcp.DelLeaseDoc)
+		router.POST(path.Uri("/dhcp/lease/del/batch"), nil,
+			s.dhcp.DelLeases, s.dhcp.DelLeasesDoc)
+		router.POST(path.Uri("/dhcp/lease/history"), nil,
+			s.dhcp.GetLeaseHistory, s.dhcp.GetLeaseHistoryDoc)
+		router.POST(path.Uri("/dhcp/rogue/report"), nil,
+			s.dhcp.GetRogueReport, s.dhcp.GetRogueReportDoc)
+		router.POST(path.Uri("/dhcp/rogue/event/list"), nil,
+			s.dhcp.GetRogueEvents, s.dhcp.GetRogueEventsDoc)
+		router.POST(path.Uri("/dhcp/oui/reload"), nil,
+			s.dhcp.ReloadOui, s.dhcp.ReloadOuiDoc)
+
+		router.POST(path.Uri("/dhcp/scope/list"), nil,
+			s.dhcp.GetScopes, s.dhcp.GetScopesDoc)
+		router.POST(path.Uri("/dhcp/scope/stats"), nil,
+			s.dhcp.GetScopeStatistics, s.dhcp.GetScopeStatisticsDoc)
+		router.POST(path.Uri("/dhcp/status"), nil,
+			s.dhcp.GetStatus, s.dhcp.GetStatusDoc)
+		router.POST(path.Uri("/dhcp/exclusion/list"), nil,
+			s.dhcp.GetExclusions, s.dhcp.GetExclusionsDoc)
+		router.POST(path.Uri("/dhcp/exclusion/add"), nil,
+			s.dhcp.AddExclusion, s.dhcp.AddExclusionDoc)
+		router.POST(path.Uri("/dhcp/exclusion/del"), nil,
+			s.dhcp.DelExclusion, s.dhcp.DelExclusionDoc)
+
+		router.POST(path.Uri("/dhcp/reservation/list"), nil,
+			s.dhcp.GetReservations, s.dhcp.GetReservationsDoc)
+		router.POST(path.Uri("/dhcp/reservation/add"), nil,
+			s.dhcp.AddReservation, s.dhcp.AddReservationDoc)
+		router.POST(path.Uri("/dhcp/reservation/del"), nil,
+			s.dhcp.DelReservation, s.dhcp.DelReservationDoc)
+
+		router.POST(path.Uri("/dhcp/failover/list"), nil,
+			s.dhcp.GetFailovers, s.dhcp.GetFailoversDoc)
+		router.POST(path.Uri("/dhcp/failover/replicate"), nil,
+			s.dhcp.ReplicateFailover, s.dhcp.ReplicateFailoverDoc)
+		router.POST(path.Uri("/dhcp/option/list"), nil,
+			s.dhcp.GetOptions, s.dhcp.GetOptionsDoc)
+		router.POST(path.Uri("/dhcp/option/effective"), nil,
+			s.dhcp.GetEffectiveOptions, s.dhcp.GetEffectiveOptionsDoc)
+		router.POST(path.Uri("/dhcp/option/set"), nil,
+			s.dhcp.SetOption, s.dhcp.SetOptionDoc)
+		router.POST(path.Uri("/dhcp/option/del"), nil,
+			s.dhcp.DelOption, s.dhcp.DelOptionDoc)
+		router.POST(path.Uri("/dhcp/option/class/list"), nil,
+			s.dhcp.GetClasses, s.dhcp.GetClassesDoc)
 	}
 
 	// DNS
@@ -43,22 +102,70 @@ func (s *Controller) InitRouting(router gtype.Router, path *gtype.Path) {
 			s.dns.AddRecord, s.dns.AddRecordDoc)
 		router.POST(path.Uri("/dns/record/del"), nil,
 			s.dns.DelRecord, s.dns.DelRecordDoc)
+		if cfg.Dhcp.Enable {
+			router.POST(path.Uri("/dns/reconcile/report"), nil,
+				s.dns.GetReconcileReport, s.dns.GetReconcileReportDoc)
+			router.POST(path.Uri("/dns/reconcile/fix"), nil,
+				s.dns.FixReconcile, s.dns.FixReconcileDoc)
+		}
 	}
 
 	// SVN
 	if cfg.Svn.Enable {
 		router.POST(path.Uri("/svn/user/all/list"), nil,
 			s.svn.GetUsers, s.svn.GetUsersDoc)
+		router.POST(path.Uri("/svn/group/all/list"), nil,
+			s.svn.GetGroups, s.svn.GetGroupsDoc)
 		router.POST(path.Uri("/svn/repository/new"), nil,
 			s.svn.NewRepository, s.svn.NewRepositoryDoc)
+		router.POST(path.Uri("/svn/repository/activity"), nil,
+			s.svn.GetRepositoryActivity, s.svn.GetRepositoryActivityDoc)
+		router.POST(path.Uri("/svn/repository/del"), nil,
+			s.svn.DeleteRepository, s.svn.DeleteRepositoryDoc)
+		router.POST(path.Uri("/svn/repository/rename"), nil,
+			s.svn.RenameRepository, s.svn.RenameRepositoryDoc)
+		router.POST(path.Uri("/svn/repository/archive"), nil,
+			s.svn.ArchiveRepository, s.svn.ArchiveRepositoryDoc)
+		router.POST(path.Uri("/svn/backup/new"), nil,
+			s.svn.NewBackup, s.svn.NewBackupDoc)
+		router.POST(path.Uri("/svn/backup/list"), nil,
+			s.svn.GetBackups, s.svn.GetBackupsDoc)
+		router.POST(path.Uri("/svn/backup/restore"), nil,
+			s.svn.RestoreBackup, s.svn.RestoreBackupDoc)
+		router.POST(path.Uri("/svn/job/list"), nil,
+			s.svn.GetJobs, s.svn.GetJobsDoc)
+		router.POST(path.Uri("/svn/hook/library/list"), nil,
+			s.svn.GetHookScripts, s.svn.GetHookScriptsDoc)
+		router.POST(path.Uri("/svn/hook/list"), nil,
+			s.svn.GetHooks, s.svn.GetHooksDoc)
+		router.POST(path.Uri("/svn/hook/install"), nil,
+			s.svn.InstallHook, s.svn.InstallHookDoc)
+		router.POST(path.Uri("/svn/hook/enable"), nil,
+			s.svn.EnableHook, s.svn.EnableHookDoc)
+		router.POST(path.Uri("/svn/hook/disable"), nil,
+			s.svn.DisableHook, s.svn.DisableHookDoc)
+		router.POST(path.Uri("/svn/hook/remove"), nil,
+			s.svn.RemoveHook, s.svn.RemoveHookDoc)
 		router.POST(path.Uri("/svn/repository/list"), nil,
 			s.svn.GetRepositories, s.svn.GetRepositoriesDoc)
 		router.POST(path.Uri("/svn/folder/list"), nil,
 			s.svn.GetFolders, s.svn.GetFoldersDoc)
+		router.POST(path.Uri("/svn/folder/new"), nil,
+			s.svn.NewFolder, s.svn.NewFolderDoc)
+		router.POST(path.Uri("/svn/branch/new"), nil,
+			s.svn.NewBranch, s.svn.NewBranchDoc)
 		router.POST(path.Uri("/svn/permission/list"), nil,
 			s.svn.GetPermissions, s.svn.GetPermissionsDoc)
 		router.POST(path.Uri("/svn/user/permission/list"), nil,
 			s.svn.GetUserPermissions, s.svn.GetUserPermissionsDoc)
+		router.POST(path.Uri("/svn/permission/effective"), nil,
+			s.svn.GetEffectivePermission, s.svn.GetEffectivePermissionDoc)
+		router.POST(path.Uri("/svn/permission/matrix"), nil,
+			s.svn.GetPermissionMatrix, s.svn.GetPermissionMatrixDoc)
+		router.POST(path.Uri("/svn/permission/copy/account"), nil,
+			s.svn.CopyAccountPermissions, s.svn.CopyAccountPermissionsDoc)
+		router.POST(path.Uri("/svn/permission/copy/path"), nil,
+			s.svn.CopyPathPermissions, s.svn.CopyPathPermissionsDoc)
 		router.POST(path.Uri("/svn/permission/add"), nil,
 			s.svn.AddPermission, s.svn.AddPermissionDoc)
 		router.POST(path.Uri("/svn/permission/mod"), nil,
@@ -92,6 +92,20 @@ func init() {
 		cfg.Site.Opt.Path = filepath.Join(rootFolder, "site", "opt")
 	}
 
+	// init path of data
+	if cfg.Dhcp.History.File == "" {
+		cfg.Dhcp.History.File = filepath.Join(rootFolder, "data", "dhcp-lease-history.json")
+	}
+	if cfg.Svn.Lifecycle.DumpFolder == "" {
+		cfg.Svn.Lifecycle.DumpFolder = filepath.Join(rootFolder, "data", "svn-dump")
+	}
+	if cfg.Svn.Backup.Folder == "" {
+		cfg.Svn.Backup.Folder = filepath.Join(rootFolder, "data", "svn-backup")
+	}
+	if cfg.Svn.Hook.Library == "" {
+		cfg.Svn.Hook.Library = filepath.Join(rootFolder, "data", "svn-hook")
+	}
+
 	// init service
 	if strings.TrimSpace(cfg.Svc.Name) == "" {
 		cfg.Svc.Name = moduleName